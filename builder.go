@@ -0,0 +1,29 @@
+package tlv
+
+// Builder constructs a TLVList with chained calls, as in NewBuilder().Add(1, v).Build().
+type Builder struct {
+	objs []TLV
+}
+
+// NewBuilder returns a new, empty Builder.
+func NewBuilder() *Builder {
+	return new(Builder)
+}
+
+// Add appends a new TLV object to the Builder.
+func (b *Builder) Add(typ byte, value []byte) *Builder {
+	b.objs = append(b.objs, New(typ, value))
+	return b
+}
+
+// AddObject appends a TLV object to the Builder.
+func (b *Builder) AddObject(obj TLV) *Builder {
+	b.objs = append(b.objs, obj)
+	return b
+}
+
+// Build returns a new TLVList holding the Builder's objects, in order.
+// Objects added to the Builder afterwards don't appear in it.
+func (b *Builder) Build() *List {
+	return ListFromSlice(b.objs)
+}
@@ -0,0 +1,33 @@
+package tlv
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	b := NewBuilder().
+		Add(TypeTest1, []byte("foo bar")).
+		Add(TypeTest2, []byte("baz quux")).
+		AddObject(NewUint32(TypeTest3, 42))
+	tlvl := b.Build()
+
+	expected := NewList()
+	expected.Add(TypeTest1, []byte("foo bar"))
+	expected.Add(TypeTest2, []byte("baz quux"))
+	expected.AddObject(NewUint32(TypeTest3, 42))
+	if !tlvl.Equal(expected) {
+		FailWithError(t, "TestBuilder",
+			fmt.Errorf("got\n%s\nexpected\n%s", tlvl, expected))
+	}
+
+	b.Add(TypeTest4, nil)
+	tlvl.Remove(TypeTest1)
+	if tlvl.Length() != 2 {
+		FailWithError(t, "TestBuilder",
+			fmt.Errorf("built list has %d objects, expected 2", tlvl.Length()))
+	} else if b.Build().Length() != 4 {
+		FailWithError(t, "TestBuilder",
+			fmt.Errorf("rebuilt list has %d objects, expected 4", b.Build().Length()))
+	}
+}
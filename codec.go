@@ -0,0 +1,613 @@
+package tlv
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"math"
+)
+
+// Codec describes the wire layout used to read and write TLV objects.
+// The zero value is a big-endian codec, which matches ReadObject and WriteObject.
+type Codec struct {
+	// ByteOrder is the byte order of the type and length fields. Nil means big-endian.
+	ByteOrder binary.ByteOrder
+	// LengthOrder, if set, is the byte order of the length field, overriding ByteOrder for it.
+	LengthOrder binary.ByteOrder
+	// TypeWidth is the number of bytes used for the type field: 1, 2 or 4. Zero means 1.
+	TypeWidth int
+	// LengthWidth is the number of bytes used for the length field: 1, 2, 4 or 8. Zero means 4.
+	LengthWidth int
+	// VarintLength encodes the length field as an unsigned LEB128 varint instead,
+	// in which case LengthWidth is ignored.
+	VarintLength bool
+	// MaxValueLength, if positive, is the largest value length ReadObject accepts.
+	// It is checked before the value buffer is allocated.
+	MaxValueLength int32
+	// Terminator, if set, enables indefinite-length objects: a length field holding its
+	// largest value marks a value that runs until the Terminator, which is not part of it.
+	// That length can't be used for definite-length objects. It is not supported with VarintLength.
+	Terminator []byte
+	// Checksum, if set, returns the hash used for a trailer written after each value
+	// and verified on read. The trailer is the hash's Sum of the value, Size bytes long.
+	Checksum func() hash.Hash
+	// BareTypes lists types that have no length or value, such as DHCP's PAD:
+	// the object is only its type field.
+	BareTypes []uint32
+	// EndTypes lists bare types that end a TLVList, such as DHCP's END.
+	// Read stops after one, leaving anything following it unread.
+	EndTypes []uint32
+	// Align, if greater than 1, pads each object with zero bytes to a multiple of Align bytes.
+	// Reading skips the padding, which may be cut short by the end of the stream.
+	Align int
+	// FixedLengths maps types whose values always have the given length to that length.
+	// Objects of those types have no length field on the wire; writing one with a value
+	// of another length fails with ErrInvalidLength. Other types are length-prefixed.
+	FixedLengths map[uint32]int32
+}
+
+// CRC32 returns a Checksum function for a CRC32 using the given polynomial,
+// such as crc32.IEEE or crc32.Castagnoli.
+func CRC32(poly uint32) func() hash.Hash {
+	table := crc32.MakeTable(poly)
+	return func() hash.Hash {
+		return crc32.New(table)
+	}
+}
+
+var defaultCodec = new(Codec)
+
+// NewCodec16 returns a Codec for the common layout of a 2-byte type and a 2-byte length,
+// both big-endian. Values longer than 65535 bytes can't be written and fail with ErrLengthOverflow.
+func NewCodec16() *Codec {
+	return &Codec{TypeWidth: 2, LengthWidth: 2}
+}
+
+// NewCodecDHCP returns a Codec for the layout of DHCP options: a 1-byte type and a 1-byte
+// length, with the bare PAD (0) and END (255) options.
+func NewCodecDHCP() *Codec {
+	return &Codec{LengthWidth: 1, BareTypes: []uint32{0}, EndTypes: []uint32{255}}
+}
+
+// isBare reports whether typ is one of the codec's bare or end types.
+func (c *Codec) isBare(typ uint32) bool {
+	return containsType(c.BareTypes, typ) || c.isEnd(typ)
+}
+
+// isEnd reports whether typ is one of the codec's end types.
+func (c *Codec) isEnd(typ uint32) bool {
+	return containsType(c.EndTypes, typ)
+}
+
+func containsType(types []uint32, typ uint32) bool {
+	for _, t := range types {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Codec) byteOrder() binary.ByteOrder {
+	if c.ByteOrder == nil {
+		return binary.BigEndian
+	}
+	return c.ByteOrder
+}
+
+func (c *Codec) lengthOrder() binary.ByteOrder {
+	if c.LengthOrder == nil {
+		return c.byteOrder()
+	}
+	return c.LengthOrder
+}
+
+func (c *Codec) typeWidth() int {
+	if c.TypeWidth == 0 {
+		return 1
+	}
+	return c.TypeWidth
+}
+
+func (c *Codec) readType(r io.Reader) (uint32, error) {
+	var buf [4]byte
+	width := c.typeWidth()
+	if width != 1 && width != 2 && width != 4 {
+		return 0, ErrInvalidWidth
+	}
+	if _, err := io.ReadFull(r, buf[:width]); err != nil {
+		return 0, err
+	}
+	return uint32(getUint(buf[:width], c.byteOrder())), nil
+}
+
+func (c *Codec) writeType(w io.Writer, typ uint32) error {
+	var buf [4]byte
+	width := c.typeWidth()
+	if width != 1 && width != 2 && width != 4 {
+		return ErrInvalidWidth
+	} else if width < 4 && typ>>(8*uint(width)) != 0 {
+		return ErrTypeOverflow
+	}
+	putUint(buf[:width], c.byteOrder(), uint64(typ))
+	_, err := w.Write(buf[:width])
+	return err
+}
+
+func (c *Codec) lengthWidth() int {
+	if c.LengthWidth == 0 {
+		return 4
+	}
+	return c.LengthWidth
+}
+
+func (c *Codec) readLength(r io.Reader) (int32, error) {
+	if c.VarintLength {
+		br, ok := r.(io.ByteReader)
+		if !ok {
+			br = byteReader{r}
+		}
+		length, err := binary.ReadUvarint(br)
+		if err != nil {
+			return 0, err
+		} else if length > math.MaxInt32 {
+			return 0, ErrLengthOverflow
+		}
+		return int32(length), nil
+	}
+
+	var buf [8]byte
+	width := c.lengthWidth()
+	if width != 1 && width != 2 && width != 4 && width != 8 {
+		return 0, ErrInvalidWidth
+	}
+	if _, err := io.ReadFull(r, buf[:width]); err != nil {
+		return 0, err
+	}
+	length := getUint(buf[:width], c.lengthOrder())
+	if len(c.Terminator) > 0 && length == maxUint(width) {
+		return indefiniteLength, nil
+	} else if length > math.MaxInt32 {
+		return 0, ErrLengthOverflow
+	}
+	return int32(length), nil
+}
+
+// indefiniteLength is returned by readLength for the indefinite-length marker.
+const indefiniteLength = -1
+
+// maxUint returns the largest unsigned integer that fits in width bytes.
+func maxUint(width int) uint64 {
+	return math.MaxUint64 >> (64 - 8*uint(width))
+}
+
+func (c *Codec) writeLength(w io.Writer, length int32) error {
+	if c.VarintLength {
+		if length < 0 {
+			return ErrLengthOverflow
+		}
+		var buf [binary.MaxVarintLen32]byte
+		n := binary.PutUvarint(buf[:], uint64(length))
+		_, err := w.Write(buf[:n])
+		return err
+	}
+
+	var buf [8]byte
+	width := c.lengthWidth()
+	if width != 1 && width != 2 && width != 4 && width != 8 {
+		return ErrInvalidWidth
+	} else if length < 0 || (width < 4 && length>>(8*uint(width)) != 0) {
+		return ErrLengthOverflow
+	} else if len(c.Terminator) > 0 && uint64(length) == maxUint(width) {
+		// That length is reserved for the indefinite-length marker.
+		return ErrLengthOverflow
+	}
+	putUint(buf[:width], c.lengthOrder(), uint64(length))
+	_, err := w.Write(buf[:width])
+	return err
+}
+
+// byteReader reads single bytes from an io.Reader, so a varint never consumes more than it needs.
+type byteReader struct {
+	r io.Reader
+}
+
+func (br byteReader) ReadByte() (byte, error) {
+	var b [1]byte
+	_, err := io.ReadFull(br.r, b[:])
+	return b[0], err
+}
+
+// getUint decodes an unsigned integer as wide as b.
+func getUint(b []byte, order binary.ByteOrder) uint64 {
+	switch len(b) {
+	case 1:
+		return uint64(b[0])
+	case 2:
+		return uint64(order.Uint16(b))
+	case 4:
+		return uint64(order.Uint32(b))
+	default:
+		return order.Uint64(b)
+	}
+}
+
+// putUint encodes v into an unsigned integer as wide as b.
+func putUint(b []byte, order binary.ByteOrder, v uint64) {
+	switch len(b) {
+	case 1:
+		b[0] = byte(v)
+	case 2:
+		order.PutUint16(b, uint16(v))
+	case 4:
+		order.PutUint32(b, uint32(v))
+	default:
+		order.PutUint64(b, v)
+	}
+}
+
+// ReadObject returns a TLV object from io.Reader using the codec's layout.
+// It returns io.EOF only if the stream ends before the object starts. Any other failure
+// is a *TLVError holding the offset within the object, wrapping io.ErrUnexpectedEOF if
+// the stream ends part way through.
+func (c *Codec) ReadObject(r io.Reader) (TLV, error) {
+	tlv, _, err := c.readObjectAlloc(r, nil)
+	return tlv, err
+}
+
+// allocFunc is called with the type and length of each object once its header is read,
+// the length being -1 for an indefinite value, and may fail the read before anything is
+// allocated. A definite value is read into the returned buffer, unless it is nil. An
+// indefinite value longer than limit fails with ErrValueTooLarge, as does one longer than
+// the codec's MaxValueLength; math.MaxInt32 sets no limit of its own.
+type allocFunc func(typ uint32, n int32) (buf []byte, limit int32, err error)
+
+// readObjectAlloc is ReadObject, also returning the number of bytes read, padding included.
+// If alloc is set, it is called for each object as described for allocFunc.
+func (c *Codec) readObjectAlloc(r io.Reader, alloc allocFunc) (TLV, int64, error) {
+	cr := &countingReader{r: r}
+	tlv, err := c.readObject(cr, alloc)
+	if err == nil {
+		err = c.skipPadding(cr)
+	}
+	if err != nil && err != io.EOF {
+		return nil, cr.n, &TLVError{Op: "read", Offset: cr.n, Err: err}
+	}
+	return tlv, cr.n, err
+}
+
+// padding returns the number of bytes that pad an object of n bytes to the codec's alignment.
+func (c *Codec) padding(n int64) int64 {
+	if c.Align <= 1 {
+		return 0
+	}
+	return (int64(c.Align) - n%int64(c.Align)) % int64(c.Align)
+}
+
+// skipPadding discards the padding following an object, where cr has counted the object's bytes.
+func (c *Codec) skipPadding(cr *countingReader) error {
+	pad := c.padding(cr.n)
+	if pad == 0 {
+		return nil
+	}
+	if _, err := io.CopyN(io.Discard, cr, pad); err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
+
+// writePadding writes the zero bytes that pad an object, where fw has counted the object's bytes.
+func (c *Codec) writePadding(fw *fullWriter) error {
+	var zero [8]byte
+	for pad := c.padding(fw.n); pad > 0; pad = c.padding(fw.n) {
+		if _, err := fw.Write(zero[:min(pad, int64(len(zero)))]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Codec) readObject(r io.Reader, alloc allocFunc) (TLV, error) {
+	tlv := new(object)
+
+	var err error
+	tlv.typ, err = c.readType(r)
+	if err != nil {
+		return nil, err
+	} else if c.isBare(tlv.typ) {
+		if alloc != nil {
+			if _, _, err = alloc(tlv.typ, 0); err != nil {
+				return nil, err
+			}
+		}
+		tlv.val = []byte{}
+		return tlv, nil
+	}
+
+	if fixed, ok := c.FixedLengths[tlv.typ]; ok && fixed < 0 {
+		return nil, ErrInvalidLength
+	} else if ok {
+		tlv.len = fixed
+	} else {
+		tlv.len, err = c.readLength(r)
+	}
+	if err == io.EOF {
+		// Only a stream ending before the type is a clean end between objects.
+		return nil, io.ErrUnexpectedEOF
+	} else if err != nil {
+		return nil, err
+	} else if c.MaxValueLength > 0 && tlv.len > c.MaxValueLength {
+		return nil, ErrValueTooLarge
+	}
+
+	limit := int32(math.MaxInt32)
+	if c.MaxValueLength > 0 {
+		limit = c.MaxValueLength
+	}
+	if alloc != nil {
+		var allocLimit int32
+		if tlv.val, allocLimit, err = alloc(tlv.typ, tlv.len); err != nil {
+			return nil, err
+		}
+		limit = min(limit, allocLimit)
+	}
+	if tlv.len == indefiniteLength {
+		tlv.val, err = c.readIndefinite(r, limit)
+		tlv.len = int32(len(tlv.val))
+	} else {
+		if tlv.val == nil {
+			tlv.val = make([]byte, tlv.Length())
+		}
+		_, err = io.ReadFull(r, tlv.val)
+	}
+	if err == io.EOF {
+		// The header was read, so running out of data here is a truncated object.
+		return nil, io.ErrUnexpectedEOF
+	} else if err != nil {
+		return nil, err
+	}
+
+	if c.Checksum != nil {
+		h := c.Checksum()
+		h.Write(tlv.val)
+		sum := make([]byte, h.Size())
+		if _, err = io.ReadFull(r, sum); err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		} else if err != nil {
+			return nil, err
+		} else if !bytes.Equal(sum, h.Sum(nil)) {
+			return nil, ErrChecksum
+		}
+	}
+
+	return tlv, nil
+}
+
+// readIndefinite reads a value up to and including the Terminator, returning the value without it.
+// A value longer than limit fails with ErrValueTooLarge.
+func (c *Codec) readIndefinite(r io.Reader, limit int32) ([]byte, error) {
+	br, ok := r.(io.ByteReader)
+	if !ok {
+		br = byteReader{r}
+	}
+
+	var val []byte
+	for !bytes.HasSuffix(val, c.Terminator) {
+		if len(val) >= int(limit)+len(c.Terminator) {
+			return nil, ErrValueTooLarge
+		}
+		b, err := br.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		val = append(val, b)
+	}
+	return val[:len(val)-len(c.Terminator)], nil
+}
+
+// WriteIndefinite writes a TLV object to io.Writer as an indefinite-length object:
+// the length field holds the marker, and the value is followed by the codec's Terminator.
+// It returns ErrTerminator if the codec has no Terminator, or the value would end early
+// because it contains the Terminator. Writer errors are reported as for WriteObject.
+func (c *Codec) WriteIndefinite(tlv TLV, w io.Writer) error {
+	fw := &fullWriter{w: w}
+	err := c.writeIndefinite(tlv, fw)
+	if err == nil {
+		err = c.writePadding(fw)
+	}
+	return fw.wrap(err)
+}
+
+func (c *Codec) writeIndefinite(tlv TLV, w io.Writer) error {
+	val := tlv.Value()
+	if len(c.Terminator) == 0 || c.VarintLength {
+		return ErrTerminator
+	} else if bytes.Index(append(val[:len(val):len(val)], c.Terminator...), c.Terminator) != len(val) {
+		return ErrTerminator
+	}
+
+	err := c.writeType(w, TypeU32(tlv))
+	if err != nil {
+		return err
+	}
+
+	var buf [8]byte
+	width := c.lengthWidth()
+	if width != 1 && width != 2 && width != 4 && width != 8 {
+		return ErrInvalidWidth
+	}
+	putUint(buf[:width], c.lengthOrder(), maxUint(width))
+	if _, err = w.Write(buf[:width]); err != nil {
+		return err
+	}
+
+	if _, err = w.Write(val); err != nil {
+		return err
+	} else if _, err = w.Write(c.Terminator); err != nil {
+		return err
+	}
+
+	if c.Checksum != nil {
+		h := c.Checksum()
+		h.Write(val)
+		_, err = w.Write(h.Sum(nil))
+	}
+	return err
+}
+
+// WriteObject writes a TLV object to io.Writer using the codec's layout.
+// An object whose Length doesn't match its Value, or of a bare type with a non-empty value,
+// fails with an error wrapping ErrInvalidLength.
+// Short writes are retried until the whole object is written. If the writer fails, or
+// stops making progress, the error is a *TLVError holding the number of bytes written.
+func (c *Codec) WriteObject(tlv TLV, w io.Writer) error {
+	fw := &fullWriter{w: w}
+	err := c.writeObject(tlv, fw)
+	if err == nil {
+		err = c.writePadding(fw)
+	}
+	return fw.wrap(err)
+}
+
+func (c *Codec) writeObject(tlv TLV, w io.Writer) error {
+	var err error
+
+	if err = Validate(tlv); err != nil {
+		return err
+	}
+	typ := TypeU32(tlv)
+	if c.isBare(typ) && tlv.Length() != 0 {
+		return ErrInvalidLength
+	}
+	fixed, isFixed := c.FixedLengths[typ]
+	if isFixed && tlv.Length() != fixed {
+		return ErrInvalidLength
+	}
+	err = c.writeType(w, typ)
+	if err != nil || c.isBare(typ) {
+		return err
+	}
+
+	if !isFixed {
+		err = c.writeLength(w, tlv.Length())
+		if err != nil {
+			return err
+		}
+	}
+
+	// A zero-length object is just its header.
+	if tlv.Length() > 0 {
+		if _, err = w.Write(tlv.Value()); err != nil {
+			return err
+		}
+	}
+
+	if c.Checksum != nil {
+		h := c.Checksum()
+		h.Write(tlv.Value())
+		_, err = w.Write(h.Sum(nil))
+		return err
+	}
+
+	return nil
+}
+
+// fullWriter retries short writes until all of p is written, counting the bytes written
+// and remembering the writer's error.
+type fullWriter struct {
+	w   io.Writer
+	n   int64
+	err error
+}
+
+func (fw *fullWriter) Write(p []byte) (int, error) {
+	var n int
+	for n < len(p) && fw.err == nil {
+		m, err := fw.w.Write(p[n:])
+		n += m
+		fw.n += int64(m)
+		if err != nil {
+			fw.err = err
+		} else if m == 0 {
+			fw.err = io.ErrShortWrite
+		}
+	}
+	return n, fw.err
+}
+
+// wrap turns an error from the writer into a *TLVError holding the bytes written.
+// Other errors, such as a type that doesn't fit the codec, are returned unchanged.
+func (fw *fullWriter) wrap(err error) error {
+	if err != nil && fw.err != nil {
+		return &TLVError{Op: "write", Offset: fw.n, Err: err}
+	}
+	return err
+}
+
+// Read takes an io.Reader and builds a TLVList from that using the codec's layout.
+// It stops at a clean EOF or after an object of one of the codec's EndTypes, which is kept.
+// If an object can't be read, the objects read so far are returned along with an
+// error naming the zero-based index of the failing object.
+func (c *Codec) Read(r io.Reader) (*List, error) {
+	tl := NewList()
+	_, err := c.readInto(tl, r, readHooks{})
+	return tl, err
+}
+
+// readHooks adjusts a readInto loop. before runs ahead of each object and ends the loop
+// with its error. after runs on each object read and decides whether it is kept and
+// whether the loop stops there. Errors from either hook are returned unwrapped.
+type readHooks struct {
+	before func(n int) error
+	after  func(n int, tlv TLV) (keep, stop bool, err error)
+}
+
+// readInto appends objects read from r onto tl until a clean EOF, returning the number
+// of bytes taken by the objects read. A *TLVError from a failing object has its offset
+// made relative to the start of r.
+func (c *Codec) readInto(tl *List, r io.Reader, hooks readHooks) (int64, error) {
+	cr := &countingReader{r: r}
+	for n := 0; ; n++ {
+		start := cr.n
+		if hooks.before != nil {
+			if err := hooks.before(n); err != nil {
+				return start, err
+			}
+		}
+
+		tlv, err := c.ReadObject(cr)
+		if err == io.EOF {
+			return start, nil
+		} else if err != nil {
+			shiftOffset(err, start)
+			return start, fmt.Errorf("TLV object %d: %w", n, err)
+		}
+
+		keep, stop := true, false
+		if hooks.after != nil {
+			if keep, stop, err = hooks.after(n, tlv); err != nil {
+				return cr.n, err
+			}
+		}
+		if keep {
+			tl.objects.PushBack(tlv)
+		}
+		if stop || c.isEnd(TypeU32(tlv)) {
+			return cr.n, nil
+		}
+	}
+}
+
+// Write writes out the TLVList to an io.Writer using the codec's layout.
+func (c *Codec) Write(tl *List, w io.Writer) error {
+	for e := tl.objects.Front(); e != nil; e = e.Next() {
+		err := c.WriteObject(e.Value.(TLV), w)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,547 @@
+package tlv
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"hash/crc64"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+func TestCodecLittleEndianRoundTrip(t *testing.T) {
+	codec := &Codec{ByteOrder: binary.LittleEndian}
+	tlv := New(TypeTest1, []byte("little endian"))
+
+	buf := new(bytes.Buffer)
+	if err := codec.WriteObject(tlv, buf); err != nil {
+		FailWithError(t, "TestCodecLittleEndianRoundTrip", err)
+	}
+	encoded := append([]byte(nil), buf.Bytes()...)
+
+	tmpTLV, err := codec.ReadObject(buf)
+	if err != nil {
+		FailWithError(t, "TestCodecLittleEndianRoundTrip", err)
+	} else if !Equal(tlv, tmpTLV) {
+		FailWithError(t, "TestCodecLittleEndianRoundTrip", errNoMatch)
+	}
+
+	buf.Reset()
+	if err := codec.WriteObject(tmpTLV, buf); err != nil {
+		FailWithError(t, "TestCodecLittleEndianRoundTrip", err)
+	}
+	if !bytes.Equal(encoded, buf.Bytes()) {
+		FailWithError(t, "TestCodecLittleEndianRoundTrip",
+			fmt.Errorf("re-encoded bytes differ"))
+	}
+}
+
+func TestCodecLittleEndianDecode(t *testing.T) {
+	codec := &Codec{ByteOrder: binary.LittleEndian}
+	raw := []byte{TypeTest2, 0x03, 0x00, 0x00, 0x00, 'a', 'b', 'c'}
+
+	tlv, err := codec.ReadObject(bytes.NewReader(raw))
+	if err != nil {
+		FailWithError(t, "TestCodecLittleEndianDecode", err)
+	}
+	if tlv.Length() != 3 {
+		FailWithError(t, "TestCodecLittleEndianDecode",
+			fmt.Errorf("length %d, expected 3", tlv.Length()))
+	}
+	if !Equal(tlv, New(TypeTest2, []byte("abc"))) {
+		FailWithError(t, "TestCodecLittleEndianDecode", errNoMatch)
+	}
+}
+
+func TestCodecDefaultIsBigEndian(t *testing.T) {
+	tlv := New(TypeTest1, []byte("foo bar"))
+	expected, err := ToBytes(tlv)
+	if err != nil {
+		FailWithError(t, "TestCodecDefaultIsBigEndian", err)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := new(Codec).WriteObject(tlv, buf); err != nil {
+		FailWithError(t, "TestCodecDefaultIsBigEndian", err)
+	}
+	if !bytes.Equal(expected, buf.Bytes()) {
+		FailWithError(t, "TestCodecDefaultIsBigEndian",
+			fmt.Errorf("zero codec does not match WriteObject"))
+	}
+}
+
+func TestCodecTypeWidth(t *testing.T) {
+	codec := &Codec{TypeWidth: 2}
+	tlv1 := NewTypeU32(0x0102, []byte("foo bar"))
+	tlv2 := NewTypeU32(0xfffe, []byte("baz quux"))
+
+	buf := new(bytes.Buffer)
+	for _, tlv := range []TLV{tlv1, tlv2} {
+		if err := codec.WriteObject(tlv, buf); err != nil {
+			FailWithError(t, "TestCodecTypeWidth", err)
+		}
+	}
+	if buf.Len() != 2*(2+4)+len("foo bar")+len("baz quux") {
+		FailWithError(t, "TestCodecTypeWidth",
+			fmt.Errorf("unexpected encoded size %d", buf.Len()))
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte{0x01, 0x02}) {
+		FailWithError(t, "TestCodecTypeWidth",
+			fmt.Errorf("type not written big-endian"))
+	}
+
+	tl, err := codec.Read(buf)
+	if err != nil {
+		FailWithError(t, "TestCodecTypeWidth", err)
+	}
+	if tl.Length() != 2 {
+		FailWithError(t, "TestCodecTypeWidth",
+			fmt.Errorf("%d records read, expected 2", tl.Length()))
+	}
+	for i, expected := range []TLV{tlv1, tlv2} {
+		tmpTLV := tl.objects.Front()
+		for j := 0; j < i; j++ {
+			tmpTLV = tmpTLV.Next()
+		}
+		if TypeU32(tmpTLV.Value.(TLV)) != TypeU32(expected) {
+			FailWithError(t, "TestCodecTypeWidth",
+				fmt.Errorf("type %#x, expected %#x",
+					TypeU32(tmpTLV.Value.(TLV)), TypeU32(expected)))
+		} else if !Equal(tmpTLV.Value.(TLV), expected) {
+			FailWithError(t, "TestCodecTypeWidth", errNoMatch)
+		}
+	}
+}
+
+func TestCodecTypeOverflow(t *testing.T) {
+	buf := new(bytes.Buffer)
+	err := new(Codec).WriteObject(NewTypeU32(0x100, nil), buf)
+	if err != ErrTypeOverflow {
+		FailWithError(t, "TestCodecTypeOverflow",
+			fmt.Errorf("expected ErrTypeOverflow, got %v", err))
+	}
+
+	err = (&Codec{TypeWidth: 3}).WriteObject(New(TypeTest1, nil), buf)
+	if err != ErrInvalidWidth {
+		FailWithError(t, "TestCodecTypeOverflow",
+			fmt.Errorf("expected ErrInvalidWidth, got %v", err))
+	}
+}
+
+func TestCodecLengthWidth(t *testing.T) {
+	tlv := New(TypeTest1, []byte{0x2a})
+
+	defaultBytes, err := ToBytes(tlv)
+	if err != nil {
+		FailWithError(t, "TestCodecLengthWidth", err)
+	}
+
+	for _, width := range []int{1, 2, 4, 8} {
+		codec := &Codec{LengthWidth: width}
+		buf := new(bytes.Buffer)
+		if err := codec.WriteObject(tlv, buf); err != nil {
+			FailWithError(t, "TestCodecLengthWidth", err)
+		}
+		if buf.Len() != 1+width+1 {
+			FailWithError(t, "TestCodecLengthWidth",
+				fmt.Errorf("width %d: encoded %d bytes", width, buf.Len()))
+		}
+		if width == 1 && 2*buf.Len() != len(defaultBytes) {
+			FailWithError(t, "TestCodecLengthWidth",
+				fmt.Errorf("1-byte width encoded %d bytes, default %d",
+					buf.Len(), len(defaultBytes)))
+		}
+
+		tmpTLV, err := codec.ReadObject(buf)
+		if err != nil {
+			FailWithError(t, "TestCodecLengthWidth", err)
+		} else if !Equal(tlv, tmpTLV) {
+			FailWithError(t, "TestCodecLengthWidth", errNoMatch)
+		}
+	}
+}
+
+func TestCodecLengthOverflow(t *testing.T) {
+	buf := new(bytes.Buffer)
+	err := (&Codec{LengthWidth: 1}).WriteObject(New(TypeTest1, make([]byte, 256)), buf)
+	if err != ErrLengthOverflow {
+		FailWithError(t, "TestCodecLengthOverflow",
+			fmt.Errorf("expected ErrLengthOverflow, got %v", err))
+	}
+
+	raw := []byte{TypeTest1, 0, 0, 0, 1, 0, 0, 0, 0}
+	_, err = (&Codec{LengthWidth: 8}).ReadObject(bytes.NewReader(raw))
+	if !errors.Is(err, ErrLengthOverflow) {
+		FailWithError(t, "TestCodecLengthOverflow",
+			fmt.Errorf("expected ErrLengthOverflow, got %v", err))
+	}
+}
+
+func TestCodecMaxValueLength(t *testing.T) {
+	codec := &Codec{MaxValueLength: 16}
+
+	raw := []byte{TypeTest1, 0x7f, 0xff, 0xff, 0xff}
+	if _, err := codec.ReadObject(bytes.NewReader(raw)); !errors.Is(err, ErrValueTooLarge) {
+		FailWithError(t, "TestCodecMaxValueLength",
+			fmt.Errorf("expected ErrValueTooLarge, got %v", err))
+	}
+
+	data, err := ToBytes(New(TypeTest1, []byte("sixteen bytes!!!")))
+	if err != nil {
+		FailWithError(t, "TestCodecMaxValueLength", err)
+	}
+	if _, err := codec.ReadObject(bytes.NewReader(data)); err != nil {
+		FailWithError(t, "TestCodecMaxValueLength", err)
+	}
+}
+
+func TestCodecVarintLength(t *testing.T) {
+	codec := &Codec{VarintLength: true}
+	expectedSizes := map[int]int{0: 1, 127: 1, 128: 2, 300: 2}
+
+	for length, varintSize := range expectedSizes {
+		tlv := New(TypeTest1, bytes.Repeat([]byte{0xa5}, length))
+
+		buf := new(bytes.Buffer)
+		if err := codec.WriteObject(tlv, buf); err != nil {
+			FailWithError(t, "TestCodecVarintLength", err)
+		}
+		if buf.Len() != 1+varintSize+length {
+			FailWithError(t, "TestCodecVarintLength",
+				fmt.Errorf("length %d: encoded %d bytes", length, buf.Len()))
+		}
+		if buf.Len() >= Size(tlv) {
+			FailWithError(t, "TestCodecVarintLength",
+				fmt.Errorf("length %d: varint encoding not smaller than fixed width", length))
+		}
+
+		tmpTLV, err := codec.ReadObject(iotest.OneByteReader(buf))
+		if err != nil {
+			FailWithError(t, "TestCodecVarintLength", err)
+		} else if !Equal(tlv, tmpTLV) {
+			FailWithError(t, "TestCodecVarintLength", errNoMatch)
+		}
+	}
+}
+
+func TestCodecChecksum(t *testing.T) {
+	codec := &Codec{Checksum: CRC32(crc32.IEEE)}
+	tlv := New(TypeTest3, []byte("gophers are everywhere!"))
+
+	buf := new(bytes.Buffer)
+	if err := codec.WriteObject(tlv, buf); err != nil {
+		FailWithError(t, "TestCodecChecksum", err)
+	}
+	if buf.Len() != Size(tlv)+4 {
+		FailWithError(t, "TestCodecChecksum",
+			fmt.Errorf("encoded %d bytes, expected %d", buf.Len(), Size(tlv)+4))
+	}
+	data := append([]byte(nil), buf.Bytes()...)
+
+	tmpTLV, err := codec.ReadObject(buf)
+	if err != nil {
+		FailWithError(t, "TestCodecChecksum", err)
+	} else if !Equal(tlv, tmpTLV) {
+		FailWithError(t, "TestCodecChecksum", errNoMatch)
+	}
+
+	data[7] ^= 0xff
+	if _, err := codec.ReadObject(bytes.NewReader(data)); !errors.Is(err, ErrChecksum) {
+		FailWithError(t, "TestCodecChecksum",
+			fmt.Errorf("expected ErrChecksum, got %v", err))
+	}
+}
+
+func TestCodecChecksumWidth(t *testing.T) {
+	table := crc64.MakeTable(crc64.ECMA)
+	codec := &Codec{Checksum: func() hash.Hash { return crc64.New(table) }}
+	tlvl := NewList()
+	tlvl.Add(TypeTest1, []byte("foo bar"))
+	tlvl.Add(TypeTest2, nil)
+
+	buf := new(bytes.Buffer)
+	if err := codec.Write(tlvl, buf); err != nil {
+		FailWithError(t, "TestCodecChecksumWidth", err)
+	}
+	if buf.Len() != tlvl.Size()+2*8 {
+		FailWithError(t, "TestCodecChecksumWidth",
+			fmt.Errorf("encoded %d bytes, expected %d", buf.Len(), tlvl.Size()+2*8))
+	}
+
+	rtlvl, err := codec.Read(buf)
+	if err != nil {
+		FailWithError(t, "TestCodecChecksumWidth", err)
+	} else if !rtlvl.Equal(tlvl) {
+		FailWithError(t, "TestCodecChecksumWidth", errNoMatch)
+	}
+}
+
+func TestCodec16(t *testing.T) {
+	tlv := NewTypeU32(0xabcd, []byte("foo bar"))
+	codec := NewCodec16()
+
+	buf := new(bytes.Buffer)
+	if err := codec.WriteObject(tlv, buf); err != nil {
+		FailWithError(t, "TestCodec16", err)
+	}
+	if buf.Len() != 4+len("foo bar") {
+		FailWithError(t, "TestCodec16",
+			fmt.Errorf("encoded %d bytes, expected a 4 byte header", buf.Len()))
+	} else if !bytes.HasPrefix(buf.Bytes(), []byte{0xab, 0xcd, 0x00, 0x07}) {
+		FailWithError(t, "TestCodec16",
+			fmt.Errorf("unexpected header % x", buf.Bytes()[:4]))
+	}
+
+	tmpTLV, err := codec.ReadObject(buf)
+	if err != nil {
+		FailWithError(t, "TestCodec16", err)
+	} else if !Equal(tlv, tmpTLV) {
+		FailWithError(t, "TestCodec16", errNoMatch)
+	}
+
+	err = codec.WriteObject(New(TypeTest1, make([]byte, 65536)), buf)
+	if err != ErrLengthOverflow {
+		FailWithError(t, "TestCodec16",
+			fmt.Errorf("expected ErrLengthOverflow, got %v", err))
+	}
+	if err := codec.WriteObject(New(TypeTest1, make([]byte, 65535)), buf); err != nil {
+		FailWithError(t, "TestCodec16", err)
+	}
+}
+
+func TestCodecIndefinite(t *testing.T) {
+	codec := &Codec{LengthWidth: 1, Terminator: []byte{0x00, 0x00}}
+	tlv1 := New(TypeTest1, []byte("streamed value"))
+	tlv2 := New(TypeTest2, []byte("baz quux"))
+
+	buf := new(bytes.Buffer)
+	if err := codec.WriteIndefinite(tlv1, buf); err != nil {
+		FailWithError(t, "TestCodecIndefinite", err)
+	}
+	if err := codec.WriteObject(tlv2, buf); err != nil {
+		FailWithError(t, "TestCodecIndefinite", err)
+	}
+	expected := append([]byte{TypeTest1, 0xff}, "streamed value\x00\x00"...)
+	if !bytes.HasPrefix(buf.Bytes(), expected) {
+		FailWithError(t, "TestCodecIndefinite",
+			fmt.Errorf("encoded % x", buf.Bytes()))
+	}
+
+	tl, err := codec.Read(buf)
+	if err != nil {
+		FailWithError(t, "TestCodecIndefinite", err)
+	}
+	expectedList := NewList()
+	expectedList.AddObject(tlv1)
+	expectedList.AddObject(tlv2)
+	if !tl.Equal(expectedList) {
+		FailWithError(t, "TestCodecIndefinite",
+			fmt.Errorf("decoded\n%s", tl))
+	}
+}
+
+func TestCodecIndefiniteErrors(t *testing.T) {
+	codec := &Codec{Terminator: []byte{0x00, 0x00}}
+	buf := new(bytes.Buffer)
+	for _, val := range [][]byte{{'a', 0, 0, 'b'}, {'a', 0}} {
+		if err := codec.WriteIndefinite(New(TypeTest1, val), buf); err != ErrTerminator {
+			FailWithError(t, "TestCodecIndefiniteErrors",
+				fmt.Errorf("% x: expected ErrTerminator, got %v", val, err))
+		}
+	}
+	if err := new(Codec).WriteIndefinite(New(TypeTest1, nil), buf); err != ErrTerminator {
+		FailWithError(t, "TestCodecIndefiniteErrors",
+			fmt.Errorf("expected ErrTerminator, got %v", err))
+	}
+
+	raw := []byte{TypeTest1, 0xff, 0xff, 0xff, 0xff, 'a', 'b', 0x00}
+	if _, err := codec.ReadObject(bytes.NewReader(raw)); !errors.Is(err, io.ErrUnexpectedEOF) {
+		FailWithError(t, "TestCodecIndefiniteErrors",
+			fmt.Errorf("expected io.ErrUnexpectedEOF, got %v", err))
+	}
+}
+
+func TestCodecConstructors(t *testing.T) {
+	codec := NewCodecDHCP()
+	codec.MaxValueLength = 4
+	codec.EndTypes[0] = 0xfe
+	if fresh := NewCodecDHCP(); fresh.MaxValueLength != 0 || fresh.EndTypes[0] != 255 {
+		FailWithError(t, "TestCodecConstructors",
+			fmt.Errorf("changes to one codec leaked into the next"))
+	}
+	NewCodec16().TypeWidth = 1
+	if NewCodec16().TypeWidth != 2 {
+		FailWithError(t, "TestCodecConstructors",
+			fmt.Errorf("changes to one codec leaked into the next"))
+	}
+}
+
+func TestCodecDHCP(t *testing.T) {
+	options := []byte{
+		53, 1, 1, // DHCP message type: DISCOVER
+		0,                                               // PAD
+		61, 7, 0x01, 0x00, 0x1a, 0x2b, 0x3c, 0x4d, 0x5e, // client identifier
+		55, 4, 1, 3, 6, 15, // parameter request list
+		0, 0, // PAD
+		255,     // END
+		0, 0, 0, // trailing padding
+	}
+
+	codec := NewCodecDHCP()
+	r := bytes.NewReader(options)
+	tlvl, err := codec.Read(r)
+	if err != nil {
+		FailWithError(t, "TestCodecDHCP", err)
+	} else if tlvl.Length() != 7 {
+		FailWithError(t, "TestCodecDHCP",
+			fmt.Errorf("%d options, expected 7", tlvl.Length()))
+	} else if r.Len() != 3 {
+		FailWithError(t, "TestCodecDHCP",
+			fmt.Errorf("%d bytes left after END, expected 3", r.Len()))
+	}
+
+	if val, err := tlvl.GetValue(53); err != nil {
+		FailWithError(t, "TestCodecDHCP", err)
+	} else if !bytes.Equal(val, []byte{1}) {
+		FailWithError(t, "TestCodecDHCP", errNoMatch)
+	}
+	if val, err := tlvl.GetValue(55); err != nil {
+		FailWithError(t, "TestCodecDHCP", err)
+	} else if !bytes.Equal(val, []byte{1, 3, 6, 15}) {
+		FailWithError(t, "TestCodecDHCP", errNoMatch)
+	}
+	if n := tlvl.Count(0); n != 3 {
+		FailWithError(t, "TestCodecDHCP", fmt.Errorf("%d PAD options, expected 3", n))
+	}
+	if last, _ := tlvl.At(-1); last.Type() != 255 || last.Length() != 0 {
+		FailWithError(t, "TestCodecDHCP", fmt.Errorf("last option %s, expected END", last))
+	}
+
+	buf := new(bytes.Buffer)
+	if err := codec.Write(tlvl, buf); err != nil {
+		FailWithError(t, "TestCodecDHCP", err)
+	} else if !bytes.Equal(buf.Bytes(), options[:len(options)-3]) {
+		FailWithError(t, "TestCodecDHCP",
+			fmt.Errorf("wrote % x", buf.Bytes()))
+	}
+
+	if err := codec.WriteObject(New(255, []byte{1}), buf); err != ErrInvalidLength {
+		FailWithError(t, "TestCodecDHCP",
+			fmt.Errorf("expected ErrInvalidLength, got %v", err))
+	}
+}
+
+func TestCodecAlign(t *testing.T) {
+	codec := &Codec{Align: 4}
+	tlvl := NewList()
+	tlvl.Add(TypeTest1, []byte("foo bar"))
+	tlvl.Add(TypeTest2, nil)
+	tlvl.Add(TypeTest3, []byte("gophers are everywhere!"))
+	tlvl.Add(TypeTest4, []byte("baz quux"))
+
+	buf := new(bytes.Buffer)
+	var offsets []int
+	for e := tlvl.objects.Front(); e != nil; e = e.Next() {
+		offsets = append(offsets, buf.Len())
+		if err := codec.WriteObject(e.Value.(TLV), buf); err != nil {
+			FailWithError(t, "TestCodecAlign", err)
+		}
+	}
+
+	// Each object is a 5-byte header and its value, padded to the next multiple of 4.
+	expected := []int{0, 12, 20, 48}
+	if fmt.Sprint(offsets) != fmt.Sprint(expected) {
+		FailWithError(t, "TestCodecAlign",
+			fmt.Errorf("offsets %v, expected %v", offsets, expected))
+	} else if buf.Len() != 64 {
+		FailWithError(t, "TestCodecAlign",
+			fmt.Errorf("%d bytes written, expected 64", buf.Len()))
+	}
+	data := buf.Bytes()
+	if !bytes.Equal(data[17:20], []byte{0, 0, 0}) {
+		FailWithError(t, "TestCodecAlign",
+			fmt.Errorf("padding % x, expected zeros", data[17:20]))
+	}
+
+	rtlvl, err := codec.Read(bytes.NewReader(data))
+	if err != nil {
+		FailWithError(t, "TestCodecAlign", err)
+	} else if !rtlvl.Equal(tlvl) {
+		FailWithError(t, "TestCodecAlign", errNoMatch)
+	}
+
+	// The final object's padding may be missing.
+	rtlvl, err = codec.Read(bytes.NewReader(data[:len(data)-3]))
+	if err != nil {
+		FailWithError(t, "TestCodecAlign", err)
+	} else if !rtlvl.Equal(tlvl) {
+		FailWithError(t, "TestCodecAlign", errNoMatch)
+	}
+}
+
+func TestCodecFixedLengths(t *testing.T) {
+	codec := &Codec{FixedLengths: map[uint32]int32{TypeTest2: 2, TypeTest4: 0}}
+	tlvl := NewList()
+	tlvl.Add(TypeTest1, []byte("foo bar"))
+	tlvl.AddUint16(TypeTest2, 0x0102)
+	tlvl.Add(TypeTest3, []byte("baz quux"))
+	tlvl.Add(TypeTest4, nil)
+
+	buf := new(bytes.Buffer)
+	if err := codec.Write(tlvl, buf); err != nil {
+		FailWithError(t, "TestCodecFixedLengths", err)
+	}
+	expected := []byte{
+		TypeTest1, 0, 0, 0, 7, 'f', 'o', 'o', ' ', 'b', 'a', 'r',
+		TypeTest2, 0x01, 0x02,
+		TypeTest3, 0, 0, 0, 8, 'b', 'a', 'z', ' ', 'q', 'u', 'u', 'x',
+		TypeTest4,
+	}
+	if !bytes.Equal(buf.Bytes(), expected) {
+		FailWithError(t, "TestCodecFixedLengths",
+			fmt.Errorf("wrote % x, expected % x", buf.Bytes(), expected))
+	}
+
+	rtlvl, err := codec.Read(buf)
+	if err != nil {
+		FailWithError(t, "TestCodecFixedLengths", err)
+	} else if !rtlvl.Equal(tlvl) {
+		FailWithError(t, "TestCodecFixedLengths", errNoMatch)
+	}
+
+	if err := codec.WriteObject(New(TypeTest2, []byte("foo")), buf); err != ErrInvalidLength {
+		FailWithError(t, "TestCodecFixedLengths",
+			fmt.Errorf("expected ErrInvalidLength, got %v", err))
+	}
+	if _, err := codec.ReadObject(bytes.NewReader([]byte{TypeTest2, 0x01})); !errors.Is(err, io.ErrUnexpectedEOF) {
+		FailWithError(t, "TestCodecFixedLengths",
+			fmt.Errorf("expected io.ErrUnexpectedEOF, got %v", err))
+	}
+}
+
+func TestCodecLengthOrder(t *testing.T) {
+	codec := &Codec{TypeWidth: 2, LengthWidth: 2, LengthOrder: binary.LittleEndian}
+	raw := []byte{0x01, 0x02, 0x04, 0x00, 0x0a, 0x0b, 0x0c, 0x0d}
+
+	tlv, err := codec.ReadObject(bytes.NewReader(raw))
+	if err != nil {
+		FailWithError(t, "TestCodecLengthOrder", err)
+	} else if TypeU32(tlv) != 0x0102 {
+		FailWithError(t, "TestCodecLengthOrder",
+			fmt.Errorf("type 0x%04x, expected big-endian 0x0102", TypeU32(tlv)))
+	} else if !bytes.Equal(tlv.Value(), []byte{0x0a, 0x0b, 0x0c, 0x0d}) {
+		FailWithError(t, "TestCodecLengthOrder", errNoMatch)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := codec.WriteObject(tlv, buf); err != nil {
+		FailWithError(t, "TestCodecLengthOrder", err)
+	} else if !bytes.Equal(buf.Bytes(), raw) {
+		FailWithError(t, "TestCodecLengthOrder",
+			fmt.Errorf("wrote % x, expected % x", buf.Bytes(), raw))
+	}
+}
@@ -0,0 +1,98 @@
+package tlv
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// CompactList is a read-only TLVList held as the encoded bytes and a single index slice,
+// so it costs no per-object allocations. Its objects share the encoded bytes.
+type CompactList struct {
+	data    []byte
+	objects []object
+}
+
+// ParseCompact builds a CompactList from data in the default layout, in one pass.
+// The list keeps data, which must not be modified while it is in use.
+// Errors match those of Read, naming the zero-based index of the failing object.
+func ParseCompact(data []byte) (*CompactList, error) {
+	cl := &CompactList{data: data}
+	for off, n := 0, 0; off < len(data); n++ {
+		if len(data)-off < headerSize {
+			return cl, cl.readError(n, int64(len(data)), io.ErrUnexpectedEOF)
+		}
+		length := binary.BigEndian.Uint32(data[off+1:])
+		if length > math.MaxInt32 {
+			return cl, cl.readError(n, int64(off+headerSize), ErrLengthOverflow)
+		} else if uint64(len(data)-off-headerSize) < uint64(length) {
+			return cl, cl.readError(n, int64(len(data)), io.ErrUnexpectedEOF)
+		}
+
+		start, end := off+headerSize, off+headerSize+int(length)
+		cl.objects = append(cl.objects, object{
+			typ: uint32(data[off]),
+			len: int32(length),
+			val: data[start:end:end],
+		})
+		off = end
+	}
+	return cl, nil
+}
+
+// ReadCompact reads r to EOF and builds a CompactList from it.
+func ReadCompact(r io.Reader) (*CompactList, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCompact(data)
+}
+
+func (cl *CompactList) readError(n int, offset int64, err error) error {
+	return fmt.Errorf("TLV object %d: %w", n, &TLVError{Op: "read", Offset: offset, Err: err})
+}
+
+// Bytes returns the encoded objects in the CompactList.
+func (cl *CompactList) Bytes() []byte {
+	return cl.data
+}
+
+// Length returns the number of objects in the CompactList.
+func (cl *CompactList) Length() int32 {
+	return int32(len(cl.objects))
+}
+
+// Get returns the first object matching the type.
+// If the type could not be found, Get returns ErrTypeNotFound.
+func (cl *CompactList) Get(typ byte) (TLV, error) {
+	for i := range cl.objects {
+		if hasType(&cl.objects[i], typ) {
+			return &cl.objects[i], nil
+		}
+	}
+	return nil, ErrTypeNotFound
+}
+
+// GetAll returns all objects matching the type.
+// If no object has the requested type, an empty slice is returned.
+func (cl *CompactList) GetAll(typ byte) []TLV {
+	ts := make([]TLV, 0)
+	for i := range cl.objects {
+		if hasType(&cl.objects[i], typ) {
+			ts = append(ts, &cl.objects[i])
+		}
+	}
+	return ts
+}
+
+// Each calls fn for each object in the CompactList, in order.
+// Iteration stops early if fn returns false.
+func (cl *CompactList) Each(fn func(TLV) bool) {
+	for i := range cl.objects {
+		if !fn(&cl.objects[i]) {
+			return
+		}
+	}
+}
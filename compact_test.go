@@ -0,0 +1,112 @@
+package tlv
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func compactTestData(t *testing.T) ([]byte, *List) {
+	tlvl := NewList()
+	tlvl.Add(TypeTest1, []byte("foo bar"))
+	tlvl.Add(TypeTest2, nil)
+	tlvl.Add(TypeTest3, []byte("gophers are everywhere!"))
+	tlvl.Add(TypeTest1, []byte("goodbye, cruel world"))
+	buf := new(bytes.Buffer)
+	if err := tlvl.Write(buf); err != nil {
+		FailWithError(t, "compactTestData", err)
+	}
+	return buf.Bytes(), tlvl
+}
+
+func TestParseCompact(t *testing.T) {
+	data, tlvl := compactTestData(t)
+
+	cl, err := ParseCompact(data)
+	if err != nil {
+		FailWithError(t, "TestParseCompact", err)
+	} else if cl.Length() != tlvl.Length() {
+		FailWithError(t, "TestParseCompact",
+			fmt.Errorf("%d objects, expected %d", cl.Length(), tlvl.Length()))
+	}
+
+	expected := tlvl.ToSlice()
+	var i int
+	cl.Each(func(tlv TLV) bool {
+		if !Equal(tlv, expected[i]) || !bytes.Equal(tlv.Value(), expected[i].Value()) {
+			FailWithError(t, "TestParseCompact", fmt.Errorf("object %d: %s", i, tlv))
+		}
+		i++
+		return true
+	})
+	if i != len(expected) {
+		FailWithError(t, "TestParseCompact", fmt.Errorf("Each visited %d objects", i))
+	}
+
+	tlv, err := cl.Get(TypeTest3)
+	if err != nil {
+		FailWithError(t, "TestParseCompact", err)
+	} else if string(tlv.Value()) != "gophers are everywhere!" {
+		FailWithError(t, "TestParseCompact", errNoMatch)
+	}
+	if _, err = cl.Get(TypeTest4); err != ErrTypeNotFound {
+		FailWithError(t, "TestParseCompact",
+			fmt.Errorf("expected ErrTypeNotFound, got %v", err))
+	}
+	if all := cl.GetAll(TypeTest1); len(all) != 2 || string(all[1].Value()) != "goodbye, cruel world" {
+		FailWithError(t, "TestParseCompact", fmt.Errorf("GetAll returned %v", all))
+	}
+
+	rcl, err := ReadCompact(bytes.NewReader(data))
+	if err != nil {
+		FailWithError(t, "TestParseCompact", err)
+	} else if !bytes.Equal(rcl.Bytes(), data) {
+		FailWithError(t, "TestParseCompact", errNoMatch)
+	}
+}
+
+func TestParseCompactTruncated(t *testing.T) {
+	data, _ := compactTestData(t)
+
+	for _, n := range []int{3, 10, len(data) - 1} {
+		_, err := ParseCompact(data[:n])
+		if !errors.Is(err, io.ErrUnexpectedEOF) || !errors.Is(err, ErrTLVRead) {
+			FailWithError(t, "TestParseCompactTruncated",
+				fmt.Errorf("%d bytes: expected io.ErrUnexpectedEOF, got %v", n, err))
+		}
+	}
+}
+
+func benchmarkListData(b *testing.B) []byte {
+	tlvl := NewList()
+	for i := 0; i < 1000; i++ {
+		tlvl.Add(byte(i), []byte("gophers are everywhere!"))
+	}
+	buf := new(bytes.Buffer)
+	if err := tlvl.Write(buf); err != nil {
+		b.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func BenchmarkReadList(b *testing.B) {
+	data := benchmarkListData(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := Read(bytes.NewReader(data)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseCompact(b *testing.B) {
+	data := benchmarkListData(b)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseCompact(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
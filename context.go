@@ -0,0 +1,25 @@
+package tlv
+
+import (
+	"context"
+	"io"
+)
+
+// ReadObjectContext returns a TLV object from io.Reader, unless ctx is already done.
+// Cancellation is checked before the read starts; a read in progress is not interrupted.
+func ReadObjectContext(ctx context.Context, r io.Reader) (TLV, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return ReadObject(r)
+}
+
+// ReadContext builds a TLVList from io.Reader, checking ctx between objects.
+// If ctx is done, the objects read so far are returned along with ctx.Err().
+func ReadContext(ctx context.Context, r io.Reader) (*List, error) {
+	tl := NewList()
+	_, err := defaultCodec.readInto(tl, r, readHooks{
+		before: func(int) error { return ctx.Err() },
+	})
+	return tl, err
+}
@@ -0,0 +1,83 @@
+package tlv
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestReadObjectContext(t *testing.T) {
+	tlvl := NewList()
+	tlvl.Add(TypeTest1, []byte("foo bar"))
+	tlvl.Add(TypeTest2, []byte("baz quux"))
+	buf := new(bytes.Buffer)
+	if err := tlvl.Write(buf); err != nil {
+		FailWithError(t, "TestReadObjectContext", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	tlv, err := ReadObjectContext(ctx, buf)
+	if err != nil {
+		FailWithError(t, "TestReadObjectContext", err)
+	} else if !Equal(tlv, New(TypeTest1, []byte("foo bar"))) {
+		FailWithError(t, "TestReadObjectContext", errNoMatch)
+	}
+
+	cancel()
+	if _, err := ReadObjectContext(ctx, buf); err != context.Canceled {
+		FailWithError(t, "TestReadObjectContext",
+			fmt.Errorf("expected context.Canceled, got %v", err))
+	}
+}
+
+func TestReadContext(t *testing.T) {
+	tlvl := NewList()
+	tlvl.Add(TypeTest1, []byte("foo bar"))
+	tlvl.Add(TypeTest2, []byte("baz quux"))
+	data := new(bytes.Buffer)
+	if err := tlvl.Write(data); err != nil {
+		FailWithError(t, "TestReadContext", err)
+	}
+
+	rtlvl, err := ReadContext(context.Background(), bytes.NewReader(data.Bytes()))
+	if err != nil {
+		FailWithError(t, "TestReadContext", err)
+	} else if rtlvl.Length() != 2 {
+		FailWithError(t, "TestReadContext",
+			fmt.Errorf("%d records read, expected 2", rtlvl.Length()))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	rtlvl, err = ReadContext(ctx, bytes.NewReader(data.Bytes()))
+	if err != context.Canceled {
+		FailWithError(t, "TestReadContext",
+			fmt.Errorf("expected context.Canceled, got %v", err))
+	} else if rtlvl.Length() != 0 {
+		FailWithError(t, "TestReadContext",
+			fmt.Errorf("%d records read after cancel", rtlvl.Length()))
+	}
+}
+
+func TestReadContextOffset(t *testing.T) {
+	tlvl := NewList()
+	tlvl.Add(TypeTest1, []byte("foo bar"))
+	tlvl.Add(TypeTest2, []byte("baz quux"))
+	data := new(bytes.Buffer)
+	if err := tlvl.Write(data); err != nil {
+		FailWithError(t, "TestReadContextOffset", err)
+	}
+
+	raw := data.Bytes()[:data.Len()-1]
+	_, err := ReadContext(context.Background(), bytes.NewReader(raw))
+	var te *TLVError
+	if !errors.As(err, &te) {
+		FailWithError(t, "TestReadContextOffset",
+			fmt.Errorf("expected a *TLVError, got %v", err))
+	} else if te.Offset != int64(len(raw)) {
+		FailWithError(t, "TestReadContextOffset",
+			fmt.Errorf("expected offset %d, got %d", len(raw), te.Offset))
+	}
+}
@@ -0,0 +1,27 @@
+package tlv
+
+import (
+	"net"
+	"time"
+)
+
+// deadlineReader sets the connection's read deadline before each read.
+type deadlineReader struct {
+	c       net.Conn
+	timeout time.Duration
+}
+
+func (dr deadlineReader) Read(p []byte) (int, error) {
+	if err := dr.c.SetReadDeadline(time.Now().Add(dr.timeout)); err != nil {
+		return 0, err
+	}
+	return dr.c.Read(p)
+}
+
+// ReadObjectDeadline returns a TLV object from a net.Conn, failing if any read of it waits
+// longer than timeout. The error from a stalled peer matches os.ErrDeadlineExceeded.
+// The read deadline is cleared on return.
+func ReadObjectDeadline(c net.Conn, timeout time.Duration) (TLV, error) {
+	defer c.SetReadDeadline(time.Time{})
+	return ReadObject(deadlineReader{c: c, timeout: timeout})
+}
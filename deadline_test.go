@@ -0,0 +1,47 @@
+package tlv
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestReadObjectDeadline(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	data, err := ToBytes(New(TypeTest1, []byte("foo bar")))
+	if err != nil {
+		FailWithError(t, "TestReadObjectDeadline", err)
+	}
+
+	go func() {
+		server.Write(data)
+		// Stall part way through the second object.
+		server.Write(data[:headerSize])
+		time.Sleep(500 * time.Millisecond)
+		server.Write(data[headerSize:])
+	}()
+
+	tlv, err := ReadObjectDeadline(client, time.Second)
+	if err != nil {
+		FailWithError(t, "TestReadObjectDeadline", err)
+	} else if !Equal(tlv, New(TypeTest1, []byte("foo bar"))) {
+		FailWithError(t, "TestReadObjectDeadline", errNoMatch)
+	}
+
+	_, err = ReadObjectDeadline(client, 50*time.Millisecond)
+	if !errors.Is(err, os.ErrDeadlineExceeded) {
+		FailWithError(t, "TestReadObjectDeadline",
+			fmt.Errorf("expected os.ErrDeadlineExceeded, got %v", err))
+	}
+	var ne net.Error
+	if !errors.As(err, &ne) || !ne.Timeout() {
+		FailWithError(t, "TestReadObjectDeadline",
+			fmt.Errorf("%v should be a timeout", err))
+	}
+}
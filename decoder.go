@@ -0,0 +1,279 @@
+package tlv
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+// ErrRepeatLimit is returned when a type appears in a stream more times than allowed.
+var ErrRepeatLimit = fmt.Errorf("TLV %s", "type repeat limit exceeded")
+
+// Decoder reads TLV objects one at a time from an input stream.
+type Decoder struct {
+	// Alloc, if set, is called to obtain the buffer for each value of n bytes,
+	// instead of allocating one. It must return a slice with a capacity of at least n;
+	// the returned object's Value aliases its first n bytes.
+	Alloc func(n int32) []byte
+	// MaxObjects, if positive, is the number of objects Next returns before failing
+	// with ErrObjectLimit on any further object.
+	MaxObjects int
+	// MaxRepeats, if positive, is the number of objects of any one type Next returns before
+	// failing with an error wrapping ErrRepeatLimit on another of that type.
+	MaxRepeats int
+	// MaxPerType, if set, holds the largest value length accepted for each type listed.
+	// A longer value fails with an error wrapping ErrValueTooLarge, before it is allocated.
+	MaxPerType map[byte]int32
+	// Codec, if set, gives the layout of the stream and its limits, such as MaxValueLength.
+	// The default layout is read otherwise.
+	Codec *Codec
+
+	r       *bufio.Reader
+	objects int
+	bytes   int64
+	offset  int64 // bytes consumed from the stream, including by failed reads
+	repeats map[uint32]int
+}
+
+// NewDecoder returns a new Decoder that reads from r.
+// The Decoder buffers its input and may read data from r beyond the objects requested.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: bufio.NewReader(r)}
+}
+
+// Buffered returns a reader of the data remaining in the Decoder's buffer.
+// The reader is valid until the next call to Next.
+func (d *Decoder) Buffered() io.Reader {
+	b, _ := d.r.Peek(d.r.Buffered())
+	return bytes.NewReader(b)
+}
+
+// Next reads the next TLV object from the stream in the Decoder's Codec layout.
+// It returns io.EOF when the stream ends cleanly between objects; other read errors
+// are as from Codec.ReadObject.
+func (d *Decoder) Next() (TLV, error) {
+	if d.MaxObjects > 0 && d.objects >= d.MaxObjects {
+		if _, err := d.r.Peek(1); err != nil {
+			return nil, err
+		}
+		return nil, ErrObjectLimit
+	}
+
+	start := d.offset
+	tlv, n, err := d.codec().readObjectAlloc(d.r, d.alloc)
+	d.offset += n
+	if err != nil {
+		shiftOffset(err, start)
+		return nil, err
+	}
+	if d.MaxRepeats > 0 {
+		// Counted only once the object is read, so a failed read doesn't use up the limit.
+		if d.repeats == nil {
+			d.repeats = make(map[uint32]int)
+		}
+		d.repeats[TypeU32(tlv)]++
+	}
+	d.objects++
+	d.bytes += n
+	return tlv, nil
+}
+
+// codec returns the Decoder's Codec, or the default one if it has none.
+func (d *Decoder) codec() *Codec {
+	if d.Codec == nil {
+		return defaultCodec
+	}
+	return d.Codec
+}
+
+// alloc applies the Decoder's limits to an object of the given type and length, then
+// obtains the buffer for its value from Alloc, if set. An indefinite value is held to
+// its type's MaxPerType limit as it is read.
+func (d *Decoder) alloc(typ uint32, length int32) ([]byte, int32, error) {
+	limit, ok := d.MaxPerType[byte(typ)]
+	if !ok || typ > 0xff {
+		limit = math.MaxInt32
+	} else if length > limit {
+		return nil, 0, fmt.Errorf("%w: type 0x%02x length %d exceeds %d", ErrValueTooLarge, typ, length, limit)
+	}
+	if d.MaxRepeats > 0 && d.repeats[typ] >= d.MaxRepeats {
+		return nil, 0, fmt.Errorf("%w: 0x%02x", ErrRepeatLimit, typ)
+	}
+
+	if d.Alloc == nil || length < 0 {
+		return nil, limit, nil
+	}
+	buf := d.Alloc(length)
+	if cap(buf) < int(length) {
+		return nil, 0, ErrInvalidLength
+	}
+	return buf[:length], limit, nil
+}
+
+// Stats returns the number of objects Next has returned and the bytes they took up in the stream.
+func (d *Decoder) Stats() (objects int, bytes int64) {
+	return d.objects, d.bytes
+}
+
+// ReadList reads objects until the stream ends cleanly, or after an object of one of the
+// Codec's EndTypes, which is kept, returning them as a TLVList. On error the objects read
+// so far are returned, along with an error naming the zero-based index of the failing object.
+func (d *Decoder) ReadList() (*List, error) {
+	tl := NewList()
+	for n := 0; ; n++ {
+		tlv, err := d.Next()
+		if err == io.EOF {
+			return tl, nil
+		} else if err != nil {
+			return tl, fmt.Errorf("TLV object %d: %w", n, err)
+		}
+		tl.objects.PushBack(tlv)
+		if d.codec().isEnd(TypeU32(tlv)) {
+			return tl, nil
+		}
+	}
+}
+
+// ReadObjectInto returns a TLV object from io.Reader, reading the value into buf if it fits.
+// Errors are as from ReadObject.
+// When it does, the returned object's Value aliases buf, and remains valid only until
+// the caller reuses buf; use Clone to keep it longer. Otherwise a new buffer is allocated.
+func ReadObjectInto(r io.Reader, buf []byte) (TLV, error) {
+	var hdr [headerSize]byte
+	typ, length, err := readHeader(r, &hdr)
+	if err != nil {
+		return nil, err
+	}
+
+	tlv := new(object)
+	tlv.typ = uint32(typ)
+	tlv.len = length
+	if int(length) <= cap(buf) {
+		tlv.val = buf[:length]
+	} else {
+		tlv.val = make([]byte, length)
+	}
+	if err := readValue(r, tlv.val); err != nil {
+		return nil, err
+	}
+	return tlv, nil
+}
+
+// ReadObjectZeroCopy reads a TLV object from a bufio.Reader without allocating, returning
+// its type and a value that aliases the reader's buffer. The value is valid only until the
+// next read from r; copy it to keep it longer. A value that doesn't fit in the buffer is
+// copied into a new slice instead.
+func ReadObjectZeroCopy(r *bufio.Reader) (byte, []byte, error) {
+	hdr, err := r.Peek(headerSize)
+	if err == io.EOF && len(hdr) > 0 {
+		return 0, nil, &TLVError{Op: "read", Offset: int64(len(hdr)), Err: io.ErrUnexpectedEOF}
+	} else if err != nil {
+		return 0, nil, err
+	}
+
+	typ := hdr[0]
+	length := binary.BigEndian.Uint32(hdr[1:])
+	if length > math.MaxInt32 {
+		return 0, nil, &TLVError{Op: "read", Offset: headerSize, Err: ErrLengthOverflow}
+	}
+
+	if headerSize+int(length) > r.Size() {
+		r.Discard(headerSize)
+		val := make([]byte, length)
+		if err := readValue(r, val); err != nil {
+			return 0, nil, err
+		}
+		return typ, val, nil
+	}
+
+	b, err := r.Peek(headerSize + int(length))
+	if err == io.EOF {
+		return 0, nil, &TLVError{Op: "read", Offset: int64(len(b)), Err: io.ErrUnexpectedEOF}
+	} else if err != nil {
+		return 0, nil, &TLVError{Op: "read", Offset: int64(len(b)), Err: err}
+	}
+	r.Discard(len(b))
+	return typ, b[headerSize:len(b):len(b)], nil
+}
+
+// SkipObject reads past the next TLV object without allocating its value,
+// returning the type and length of the skipped object.
+// Errors are as from ReadObject.
+func SkipObject(r io.Reader) (byte, int32, error) {
+	var hdr [headerSize]byte
+	typ, length, err := readHeader(r, &hdr)
+	if err != nil {
+		return 0, 0, err
+	}
+	if n, err := io.CopyN(io.Discard, r, int64(length)); err == io.EOF {
+		return typ, length, &TLVError{Op: "read", Offset: headerSize + n, Err: io.ErrUnexpectedEOF}
+	} else if err != nil {
+		return typ, length, &TLVError{Op: "read", Offset: headerSize + n, Err: err}
+	}
+	return typ, length, nil
+}
+
+// ValidateStream reads and discards TLV objects until the stream ends cleanly, without
+// allocating their values. It returns the number of complete objects seen, and the first
+// error, which names the zero-based index of the failing object.
+func ValidateStream(r io.Reader) (int, error) {
+	cr := &countingReader{r: r}
+	for n := 0; ; n++ {
+		start := cr.n
+		if _, _, err := SkipObject(cr); err == io.EOF {
+			return n, nil
+		} else if err != nil {
+			shiftOffset(err, start)
+			return n, fmt.Errorf("TLV object %d: %w", n, err)
+		}
+	}
+}
+
+// PeekType returns the type of the next TLV object without consuming any bytes.
+// It returns io.EOF if the stream is empty.
+func PeekType(r *bufio.Reader) (byte, error) {
+	b, err := r.Peek(1)
+	if err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+// ReadHeader reads the type and length of the next TLV object, leaving its value unread.
+// It returns io.EOF only if the stream ends before the header starts; any other failure
+// is a *TLVError, as from ReadObject.
+func ReadHeader(r io.Reader) (byte, int32, error) {
+	var hdr [headerSize]byte
+	return readHeader(r, &hdr)
+}
+
+// readHeader reads a type byte and a 4-byte big-endian length into hdr.
+// It returns io.EOF only if the stream ends before the header starts; any other
+// failure is a *TLVError, as from ReadObject.
+func readHeader(r io.Reader, hdr *[headerSize]byte) (byte, int32, error) {
+	if n, err := io.ReadFull(r, hdr[:]); err == io.EOF {
+		return 0, 0, err
+	} else if err != nil {
+		return 0, 0, &TLVError{Op: "read", Offset: int64(n), Err: err}
+	}
+
+	length := binary.BigEndian.Uint32(hdr[1:])
+	if length > math.MaxInt32 {
+		return 0, 0, &TLVError{Op: "read", Offset: headerSize, Err: ErrLengthOverflow}
+	}
+	return hdr[0], int32(length), nil
+}
+
+// readValue fills val from r, following a header read by readHeader. Running out of data
+// is always a truncated object, and failures are a *TLVError, as from ReadObject.
+func readValue(r io.Reader, val []byte) error {
+	if n, err := io.ReadFull(r, val); err == io.EOF {
+		return &TLVError{Op: "read", Offset: headerSize, Err: io.ErrUnexpectedEOF}
+	} else if err != nil {
+		return &TLVError{Op: "read", Offset: headerSize + int64(n), Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,675 @@
+package tlv
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestDecoderNext(t *testing.T) {
+	tlvs := []TLV{
+		New(TypeTest1, []byte("foo bar")),
+		New(TypeTest2, nil),
+		New(TypeTest3, []byte("gophers are everywhere!")),
+	}
+	buf := new(bytes.Buffer)
+	for _, tlv := range tlvs {
+		if err := WriteObject(tlv, buf); err != nil {
+			FailWithError(t, "TestDecoderNext", err)
+		}
+	}
+
+	dec := NewDecoder(buf)
+	for _, expected := range tlvs {
+		tlv, err := dec.Next()
+		if err != nil {
+			FailWithError(t, "TestDecoderNext", err)
+		} else if !Equal(tlv, expected) {
+			FailWithError(t, "TestDecoderNext", errNoMatch)
+		}
+	}
+	if _, err := dec.Next(); err != io.EOF {
+		FailWithError(t, "TestDecoderNext",
+			fmt.Errorf("expected io.EOF, got %v", err))
+	}
+}
+
+func TestDecoderTruncated(t *testing.T) {
+	data, err := ToBytes(New(TypeTest1, []byte("foo bar")))
+	if err != nil {
+		FailWithError(t, "TestDecoderTruncated", err)
+	}
+
+	for _, n := range []int{3, 5, len(data) - 1} {
+		_, err := NewDecoder(bytes.NewReader(data[:n])).Next()
+		var te *TLVError
+		if !errors.Is(err, io.ErrUnexpectedEOF) || !errors.As(err, &te) {
+			FailWithError(t, "TestDecoderTruncated",
+				fmt.Errorf("%d bytes: expected io.ErrUnexpectedEOF, got %v", n, err))
+		} else if te.Offset != int64(n) {
+			FailWithError(t, "TestDecoderTruncated",
+				fmt.Errorf("%d bytes: expected offset %d, got %d", n, n, te.Offset))
+		}
+	}
+}
+
+func TestReadObjectInto(t *testing.T) {
+	data, err := ToBytes(New(TypeTest1, []byte("foo bar")))
+	if err != nil {
+		FailWithError(t, "TestReadObjectInto", err)
+	}
+
+	buf := make([]byte, 16)
+	tlv, err := ReadObjectInto(bytes.NewReader(data), buf)
+	if err != nil {
+		FailWithError(t, "TestReadObjectInto", err)
+	} else if !Equal(tlv, New(TypeTest1, []byte("foo bar"))) {
+		FailWithError(t, "TestReadObjectInto", errNoMatch)
+	} else if &tlv.Value()[0] != &buf[0] {
+		FailWithError(t, "TestReadObjectInto",
+			fmt.Errorf("value not read into the provided buffer"))
+	}
+
+	small := make([]byte, 2)
+	tlv, err = ReadObjectInto(bytes.NewReader(data), small)
+	if err != nil {
+		FailWithError(t, "TestReadObjectInto", err)
+	} else if !Equal(tlv, New(TypeTest1, []byte("foo bar"))) {
+		FailWithError(t, "TestReadObjectInto", errNoMatch)
+	}
+}
+
+func benchmarkData(b *testing.B) []byte {
+	data, err := ToBytes(New(TypeTest1, make([]byte, 256)))
+	if err != nil {
+		b.Fatal(err)
+	}
+	return data
+}
+
+func BenchmarkReadObject(b *testing.B) {
+	data := benchmarkData(b)
+	r := bytes.NewReader(data)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		r.Reset(data)
+		if _, err := ReadObject(r); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkReadObjectInto(b *testing.B) {
+	data := benchmarkData(b)
+	r := bytes.NewReader(data)
+	buf := make([]byte, 256)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		r.Reset(data)
+		if _, err := ReadObjectInto(r, buf); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestPeekType(t *testing.T) {
+	tlv := New(TypeTest3, []byte("gophers are everywhere!"))
+	data, err := ToBytes(tlv)
+	if err != nil {
+		FailWithError(t, "TestPeekType", err)
+	}
+
+	r := bufio.NewReader(bytes.NewReader(data))
+	typ, err := PeekType(r)
+	if err != nil {
+		FailWithError(t, "TestPeekType", err)
+	} else if typ != TypeTest3 {
+		FailWithError(t, "TestPeekType",
+			fmt.Errorf("peeked type %d, expected %d", typ, TypeTest3))
+	}
+
+	tmpTLV, err := ReadObject(r)
+	if err != nil {
+		FailWithError(t, "TestPeekType", err)
+	} else if !Equal(tlv, tmpTLV) {
+		FailWithError(t, "TestPeekType", errNoMatch)
+	}
+
+	if _, err := PeekType(r); err != io.EOF {
+		FailWithError(t, "TestPeekType",
+			fmt.Errorf("expected io.EOF, got %v", err))
+	}
+}
+
+func TestSkipObject(t *testing.T) {
+	buf := new(bytes.Buffer)
+	large := New(TypeTest1, make([]byte, 1<<20))
+	next := New(TypeTest2, []byte("baz quux"))
+	if err := WriteObject(large, buf); err != nil {
+		FailWithError(t, "TestSkipObject", err)
+	}
+	if err := WriteObject(next, buf); err != nil {
+		FailWithError(t, "TestSkipObject", err)
+	}
+
+	typ, length, err := SkipObject(buf)
+	if err != nil {
+		FailWithError(t, "TestSkipObject", err)
+	} else if typ != TypeTest1 || length != 1<<20 {
+		FailWithError(t, "TestSkipObject",
+			fmt.Errorf("skipped type %d length %d", typ, length))
+	}
+
+	tmpTLV, err := ReadObject(buf)
+	if err != nil {
+		FailWithError(t, "TestSkipObject", err)
+	} else if !Equal(tmpTLV, next) {
+		FailWithError(t, "TestSkipObject", errNoMatch)
+	}
+
+	data, _ := ToBytes(next)
+	if _, _, err := SkipObject(bytes.NewReader(data[:len(data)-1])); !errors.Is(err, io.ErrUnexpectedEOF) || !errors.Is(err, ErrTLVRead) {
+		FailWithError(t, "TestSkipObject",
+			fmt.Errorf("expected io.ErrUnexpectedEOF, got %v", err))
+	}
+}
+
+func TestDecoderBuffered(t *testing.T) {
+	data, err := ToBytes(New(TypeTest1, []byte("foo bar")))
+	if err != nil {
+		FailWithError(t, "TestDecoderBuffered", err)
+	}
+	trailer := []byte("opaque trailing bytes")
+
+	r := bytes.NewReader(append(data, trailer...))
+	dec := NewDecoder(r)
+	if _, err := dec.Next(); err != nil {
+		FailWithError(t, "TestDecoderBuffered", err)
+	}
+
+	rest, err := ioutil.ReadAll(io.MultiReader(dec.Buffered(), r))
+	if err != nil {
+		FailWithError(t, "TestDecoderBuffered", err)
+	} else if !bytes.Equal(rest, trailer) {
+		FailWithError(t, "TestDecoderBuffered",
+			fmt.Errorf("remaining %q, expected %q", rest, trailer))
+	}
+}
+
+func TestDecoderAlloc(t *testing.T) {
+	tlvl := NewList()
+	tlvl.Add(TypeTest1, []byte("foo bar"))
+	tlvl.Add(TypeTest2, []byte("baz quux"))
+	buf := new(bytes.Buffer)
+	if err := tlvl.Write(buf); err != nil {
+		FailWithError(t, "TestDecoderAlloc", err)
+	}
+
+	arena := make([]byte, 64)
+	var calls, used int
+	dec := NewDecoder(buf)
+	dec.Alloc = func(n int32) []byte {
+		calls++
+		b := arena[used : used+int(n)]
+		used += int(n)
+		return b
+	}
+
+	var i int
+	for ; ; i++ {
+		tlv, err := dec.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			FailWithError(t, "TestDecoderAlloc", err)
+		}
+		if expected, _ := tlvl.GetN(tlv.Type(), 0); !Equal(tlv, expected) {
+			FailWithError(t, "TestDecoderAlloc", errNoMatch)
+		}
+	}
+	if calls != 2 {
+		FailWithError(t, "TestDecoderAlloc",
+			fmt.Errorf("Alloc called %d times, expected 2", calls))
+	}
+	if string(arena[:used]) != "foo barbaz quux" {
+		FailWithError(t, "TestDecoderAlloc",
+			fmt.Errorf("values not read into the arena: %q", arena[:used]))
+	}
+
+	data, _ := ToBytes(New(TypeTest1, []byte("foo bar")))
+	dec = NewDecoder(bytes.NewReader(data))
+	dec.Alloc = func(n int32) []byte { return nil }
+	if _, err := dec.Next(); !errors.Is(err, ErrInvalidLength) {
+		FailWithError(t, "TestDecoderAlloc",
+			fmt.Errorf("expected ErrInvalidLength, got %v", err))
+	}
+}
+
+func TestDecoderLimits(t *testing.T) {
+	tlvl := NewList()
+	tlvl.Add(TypeTest1, []byte("foo bar"))
+	tlvl.Add(TypeTest2, []byte("baz quux"))
+	tlvl.Add(TypeTest1, []byte("goodbye, cruel world"))
+	tlvl.Add(TypeTest1, []byte("gophers are everywhere!"))
+	buf := new(bytes.Buffer)
+	if err := tlvl.Write(buf); err != nil {
+		FailWithError(t, "TestDecoderLimits", err)
+	}
+	data := buf.Bytes()
+
+	dec := NewDecoder(bytes.NewReader(data))
+	dec.MaxObjects, dec.MaxRepeats = 4, 3
+	if rtlvl, err := dec.ReadList(); err != nil {
+		FailWithError(t, "TestDecoderLimits", err)
+	} else if !rtlvl.Equal(tlvl) {
+		FailWithError(t, "TestDecoderLimits", errNoMatch)
+	}
+
+	dec = NewDecoder(bytes.NewReader(data))
+	dec.MaxObjects = 3
+	rtlvl, err := dec.ReadList()
+	if !errors.Is(err, ErrObjectLimit) {
+		FailWithError(t, "TestDecoderLimits",
+			fmt.Errorf("expected ErrObjectLimit, got %v", err))
+	} else if rtlvl.Length() != 3 {
+		FailWithError(t, "TestDecoderLimits",
+			fmt.Errorf("%d objects read, expected 3", rtlvl.Length()))
+	}
+
+	dec = NewDecoder(bytes.NewReader(data))
+	dec.MaxRepeats = 2
+	rtlvl, err = dec.ReadList()
+	if !errors.Is(err, ErrRepeatLimit) {
+		FailWithError(t, "TestDecoderLimits",
+			fmt.Errorf("expected ErrRepeatLimit, got %v", err))
+	} else if !strings.Contains(err.Error(), "0x00") {
+		FailWithError(t, "TestDecoderLimits",
+			fmt.Errorf("%v should name type 0x00", err))
+	} else if rtlvl.Length() != 3 {
+		FailWithError(t, "TestDecoderLimits",
+			fmt.Errorf("%d objects read, expected 3", rtlvl.Length()))
+	}
+}
+
+func TestReadObjectZeroCopy(t *testing.T) {
+	tlvs := []TLV{
+		New(TypeTest1, []byte("foo bar")),
+		New(TypeTest2, nil),
+		New(TypeTest3, bytes.Repeat([]byte("gophers are everywhere!"), 2)),
+	}
+	buf := new(bytes.Buffer)
+	for _, tlv := range tlvs {
+		if err := WriteObject(tlv, buf); err != nil {
+			FailWithError(t, "TestReadObjectZeroCopy", err)
+		}
+	}
+	data := buf.Bytes()
+
+	// The smallest buffer bufio allows is 16 bytes, too small for the last object.
+	r := bufio.NewReaderSize(bytes.NewReader(data), 16)
+	for _, expected := range tlvs {
+		typ, val, err := ReadObjectZeroCopy(r)
+		if err != nil {
+			FailWithError(t, "TestReadObjectZeroCopy", err)
+		} else if !Equal(New(typ, val), expected) {
+			FailWithError(t, "TestReadObjectZeroCopy", errNoMatch)
+		}
+	}
+	if _, _, err := ReadObjectZeroCopy(r); err != io.EOF {
+		FailWithError(t, "TestReadObjectZeroCopy",
+			fmt.Errorf("expected io.EOF, got %v", err))
+	}
+
+	for _, n := range []int{3, 10} {
+		r = bufio.NewReader(bytes.NewReader(data[:n]))
+		_, _, err := ReadObjectZeroCopy(r)
+		var te *TLVError
+		if !errors.Is(err, io.ErrUnexpectedEOF) || !errors.As(err, &te) {
+			FailWithError(t, "TestReadObjectZeroCopy",
+				fmt.Errorf("%d bytes: expected io.ErrUnexpectedEOF, got %v", n, err))
+		} else if te.Offset != int64(n) {
+			FailWithError(t, "TestReadObjectZeroCopy",
+				fmt.Errorf("%d bytes: expected offset %d, got %d", n, n, te.Offset))
+		}
+	}
+}
+
+func TestReadObjectZeroCopyAllocs(t *testing.T) {
+	data, err := ToBytes(New(TypeTest1, []byte("gophers are everywhere!")))
+	if err != nil {
+		FailWithError(t, "TestReadObjectZeroCopyAllocs", err)
+	}
+	br := bytes.NewReader(data)
+	r := bufio.NewReader(br)
+	allocs := testing.AllocsPerRun(100, func() {
+		br.Reset(data)
+		r.Reset(br)
+		if _, _, err := ReadObjectZeroCopy(r); err != nil {
+			FailWithError(t, "TestReadObjectZeroCopyAllocs", err)
+		}
+	})
+	if allocs != 0 {
+		FailWithError(t, "TestReadObjectZeroCopyAllocs",
+			fmt.Errorf("%v allocations per read, expected 0", allocs))
+	}
+}
+
+func BenchmarkReadObjectZeroCopy(b *testing.B) {
+	data := benchmarkData(b)
+	br := bytes.NewReader(data)
+	r := bufio.NewReader(br)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		br.Reset(data)
+		r.Reset(br)
+		if _, _, err := ReadObjectZeroCopy(r); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestDecoderCodec(t *testing.T) {
+	codec := &Codec{TypeWidth: 2, LengthWidth: 2}
+	tlvl := NewList()
+	tlvl.objects.PushBack(NewTypeU32(0x1234, []byte("foo bar")))
+	tlvl.Add(TypeTest2, []byte("gophers are everywhere!"))
+	buf := new(bytes.Buffer)
+	if err := codec.Write(tlvl, buf); err != nil {
+		FailWithError(t, "TestDecoderCodec", err)
+	}
+	data := buf.Bytes()
+
+	dec := NewDecoder(bytes.NewReader(data))
+	dec.Codec = codec
+	rtlvl, err := dec.ReadList()
+	if err != nil {
+		FailWithError(t, "TestDecoderCodec", err)
+	} else if !rtlvl.Equal(tlvl) {
+		FailWithError(t, "TestDecoderCodec",
+			fmt.Errorf("got\n%s\nexpected\n%s", rtlvl, tlvl))
+	} else if _, n := dec.Stats(); n != int64(len(data)) {
+		FailWithError(t, "TestDecoderCodec",
+			fmt.Errorf("%d bytes counted, expected %d", n, len(data)))
+	}
+
+	dec = NewDecoder(bytes.NewReader(data))
+	dec.Codec = &Codec{TypeWidth: 2, LengthWidth: 2, MaxValueLength: 16}
+	if _, err := dec.Next(); err != nil {
+		FailWithError(t, "TestDecoderCodec", err)
+	}
+	if _, err := dec.Next(); !errors.Is(err, ErrValueTooLarge) {
+		FailWithError(t, "TestDecoderCodec",
+			fmt.Errorf("expected ErrValueTooLarge, got %v", err))
+	}
+}
+
+func TestDecoderMaxPerType(t *testing.T) {
+	buf := new(bytes.Buffer)
+	WriteObject(New(TypeTest1, []byte("foo bar")), buf)
+	WriteObject(New(TypeTest2, []byte("gophers are everywhere!")), buf)
+	WriteObject(New(TypeTest3, []byte("goodbye, cruel world")), buf)
+
+	dec := NewDecoder(buf)
+	dec.MaxPerType = map[byte]int32{TypeTest1: 16, TypeTest2: 8}
+	if tlv, err := dec.Next(); err != nil {
+		FailWithError(t, "TestDecoderMaxPerType", err)
+	} else if string(tlv.Value()) != "foo bar" {
+		FailWithError(t, "TestDecoderMaxPerType", errNoMatch)
+	}
+
+	_, err := dec.Next()
+	if !errors.Is(err, ErrValueTooLarge) {
+		FailWithError(t, "TestDecoderMaxPerType",
+			fmt.Errorf("expected ErrValueTooLarge, got %v", err))
+	} else if !strings.Contains(err.Error(), "0x01") {
+		FailWithError(t, "TestDecoderMaxPerType",
+			fmt.Errorf("%v should name type 0x01", err))
+	}
+}
+
+func TestDecoderMaxPerTypeIndefinite(t *testing.T) {
+	codec := &Codec{Terminator: []byte{0x00, 0x00}}
+	buf := new(bytes.Buffer)
+	if err := codec.WriteIndefinite(New(TypeTest1, []byte("foo")), buf); err != nil {
+		FailWithError(t, "TestDecoderMaxPerTypeIndefinite", err)
+	}
+	if err := codec.WriteIndefinite(New(TypeTest1, bytes.Repeat([]byte("gophers!"), 12)), buf); err != nil {
+		FailWithError(t, "TestDecoderMaxPerTypeIndefinite", err)
+	}
+
+	dec := NewDecoder(buf)
+	dec.Codec = codec
+	dec.MaxPerType = map[byte]int32{TypeTest1: 4}
+	if tlv, err := dec.Next(); err != nil {
+		FailWithError(t, "TestDecoderMaxPerTypeIndefinite", err)
+	} else if string(tlv.Value()) != "foo" {
+		FailWithError(t, "TestDecoderMaxPerTypeIndefinite", errNoMatch)
+	}
+	if _, err := dec.Next(); !errors.Is(err, ErrValueTooLarge) {
+		FailWithError(t, "TestDecoderMaxPerTypeIndefinite",
+			fmt.Errorf("expected ErrValueTooLarge, got %v", err))
+	}
+}
+
+func TestHeaderProxy(t *testing.T) {
+	in := new(bytes.Buffer)
+	WriteObject(New(TypeTest1, []byte("foo bar")), in)
+	WriteObject(New(TypeTest2, []byte("gophers are everywhere!")), in)
+
+	out := new(bytes.Buffer)
+	for {
+		typ, length, err := ReadHeader(in)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			FailWithError(t, "TestHeaderProxy", err)
+		}
+		if typ == TypeTest2 {
+			typ = TypeTest6
+		}
+		if err = WriteHeader(out, typ, length); err != nil {
+			FailWithError(t, "TestHeaderProxy", err)
+		} else if _, err = io.CopyN(out, in, int64(length)); err != nil {
+			FailWithError(t, "TestHeaderProxy", err)
+		}
+	}
+
+	tlvl, err := Read(out)
+	if err != nil {
+		FailWithError(t, "TestHeaderProxy", err)
+	}
+	expected := NewList()
+	expected.Add(TypeTest1, []byte("foo bar"))
+	expected.Add(TypeTest6, []byte("gophers are everywhere!"))
+	if !tlvl.Equal(expected) {
+		FailWithError(t, "TestHeaderProxy",
+			fmt.Errorf("got\n%s\nexpected\n%s", tlvl, expected))
+	}
+
+	if _, _, err = ReadHeader(bytes.NewReader([]byte{TypeTest1, 0})); !errors.Is(err, io.ErrUnexpectedEOF) || !errors.Is(err, ErrTLVRead) {
+		FailWithError(t, "TestHeaderProxy",
+			fmt.Errorf("expected io.ErrUnexpectedEOF, got %v", err))
+	}
+	if err = WriteHeader(out, TypeTest1, -1); err != ErrLengthOverflow {
+		FailWithError(t, "TestHeaderProxy",
+			fmt.Errorf("expected ErrLengthOverflow, got %v", err))
+	}
+}
+
+func TestDecoderStats(t *testing.T) {
+	buf := new(bytes.Buffer)
+	WriteObject(New(TypeTest1, []byte("foo bar")), buf)
+	WriteObject(New(TypeTest2, nil), buf)
+	WriteObject(New(TypeTest3, []byte("gophers are everywhere!")), buf)
+	size := int64(buf.Len())
+
+	dec := NewDecoder(buf)
+	if objects, n := dec.Stats(); objects != 0 || n != 0 {
+		FailWithError(t, "TestDecoderStats",
+			fmt.Errorf("new decoder stats %d objects %d bytes", objects, n))
+	}
+
+	dec.Next()
+	if objects, n := dec.Stats(); objects != 1 || n != 12 {
+		FailWithError(t, "TestDecoderStats",
+			fmt.Errorf("%d objects %d bytes, expected 1 and 12", objects, n))
+	}
+
+	if _, err := dec.ReadList(); err != nil {
+		FailWithError(t, "TestDecoderStats", err)
+	}
+	if objects, n := dec.Stats(); objects != 3 || n != size {
+		FailWithError(t, "TestDecoderStats",
+			fmt.Errorf("%d objects %d bytes, expected 3 and %d", objects, n, size))
+	}
+}
+
+func TestValidateStream(t *testing.T) {
+	buf := new(bytes.Buffer)
+	WriteObject(New(TypeTest1, []byte("foo bar")), buf)
+	WriteObject(New(TypeTest2, nil), buf)
+	WriteObject(New(TypeTest3, []byte("gophers are everywhere!")), buf)
+	data := buf.Bytes()
+
+	if n, err := ValidateStream(bytes.NewReader(data)); err != nil {
+		FailWithError(t, "TestValidateStream", err)
+	} else if n != 3 {
+		FailWithError(t, "TestValidateStream", fmt.Errorf("%d objects, expected 3", n))
+	}
+
+	n, err := ValidateStream(bytes.NewReader(data[:len(data)-1]))
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		FailWithError(t, "TestValidateStream",
+			fmt.Errorf("expected io.ErrUnexpectedEOF, got %v", err))
+	} else if n != 2 {
+		FailWithError(t, "TestValidateStream", fmt.Errorf("%d objects, expected 2", n))
+	} else if !strings.Contains(err.Error(), "object 2") {
+		FailWithError(t, "TestValidateStream", fmt.Errorf("%v should name object 2", err))
+	}
+}
+
+func TestStreamOffsets(t *testing.T) {
+	tlvl := NewList()
+	tlvl.Add(TypeTest1, []byte("foo bar"))
+	tlvl.Add(TypeTest2, []byte("baz quux"))
+	buf := new(bytes.Buffer)
+	if err := tlvl.Write(buf); err != nil {
+		FailWithError(t, "TestStreamOffsets", err)
+	}
+	raw := buf.Bytes()[:buf.Len()-1]
+
+	readers := map[string]func(r io.Reader) error{
+		"Read": func(r io.Reader) error {
+			_, err := Read(r)
+			return err
+		},
+		"Decoder.ReadList": func(r io.Reader) error {
+			_, err := NewDecoder(r).ReadList()
+			return err
+		},
+		"ValidateStream": func(r io.Reader) error {
+			_, err := ValidateStream(r)
+			return err
+		},
+		"TranslateStream": func(r io.Reader) error {
+			return TranslateStream(r, ioutil.Discard, nil)
+		},
+	}
+	for name, read := range readers {
+		err := read(bytes.NewReader(raw))
+		var te *TLVError
+		if !errors.As(err, &te) || !errors.Is(err, io.ErrUnexpectedEOF) {
+			FailWithError(t, "TestStreamOffsets",
+				fmt.Errorf("%s: expected a truncation *TLVError, got %v", name, err))
+		} else if te.Op != "read" || te.Offset != int64(len(raw)) {
+			FailWithError(t, "TestStreamOffsets",
+				fmt.Errorf("%s: %s error at offset %d, expected read at %d", name, te.Op, te.Offset, len(raw)))
+		}
+	}
+}
+
+func TestDecoderEndTypes(t *testing.T) {
+	options := []byte{
+		53, 1, 1, // DHCP message type: DISCOVER
+		0,       // PAD
+		255,     // END
+		0, 0, 0, // trailing padding
+	}
+
+	dec := NewDecoder(bytes.NewReader(options))
+	dec.Codec = NewCodecDHCP()
+	tlvl, err := dec.ReadList()
+	if err != nil {
+		FailWithError(t, "TestDecoderEndTypes", err)
+	} else if tlvl.Length() != 3 {
+		FailWithError(t, "TestDecoderEndTypes",
+			fmt.Errorf("%d options read, expected 3", tlvl.Length()))
+	} else if last, _ := tlvl.At(-1); last.Type() != 255 {
+		FailWithError(t, "TestDecoderEndTypes", fmt.Errorf("last option %s, expected END", last))
+	}
+	if rest, _ := ioutil.ReadAll(dec.Buffered()); !bytes.Equal(rest, []byte{0, 0, 0}) {
+		FailWithError(t, "TestDecoderEndTypes",
+			fmt.Errorf("% x left after END, expected the trailing padding", rest))
+	}
+}
+
+func TestDecoderRepeatsAfterFailure(t *testing.T) {
+	codec := &Codec{Checksum: CRC32(crc32.IEEE)}
+	buf := new(bytes.Buffer)
+	if err := codec.WriteObject(New(TypeTest1, []byte("foo bar")), buf); err != nil {
+		FailWithError(t, "TestDecoderRepeatsAfterFailure", err)
+	}
+	data := buf.Bytes()
+	data[len(data)-1] ^= 0xff
+	if err := codec.WriteObject(New(TypeTest1, []byte("baz quux")), buf); err != nil {
+		FailWithError(t, "TestDecoderRepeatsAfterFailure", err)
+	}
+
+	dec := NewDecoder(buf)
+	dec.Codec = codec
+	dec.MaxRepeats = 1
+	if _, err := dec.Next(); !errors.Is(err, ErrChecksum) {
+		FailWithError(t, "TestDecoderRepeatsAfterFailure",
+			fmt.Errorf("expected ErrChecksum, got %v", err))
+	}
+	if tlv, err := dec.Next(); err != nil {
+		FailWithError(t, "TestDecoderRepeatsAfterFailure", err)
+	} else if string(tlv.Value()) != "baz quux" {
+		FailWithError(t, "TestDecoderRepeatsAfterFailure", errNoMatch)
+	}
+}
+
+func TestDecoderOffsetAfterFailure(t *testing.T) {
+	codec := &Codec{Checksum: CRC32(crc32.IEEE)}
+	buf := new(bytes.Buffer)
+	if err := codec.WriteObject(New(TypeTest1, []byte("foo bar")), buf); err != nil {
+		FailWithError(t, "TestDecoderOffsetAfterFailure", err)
+	}
+	buf.Bytes()[buf.Len()-1] ^= 0xff
+	if err := codec.WriteObject(New(TypeTest2, []byte("baz quux")), buf); err != nil {
+		FailWithError(t, "TestDecoderOffsetAfterFailure", err)
+	}
+	raw := buf.Bytes()[:buf.Len()-1]
+
+	dec := NewDecoder(bytes.NewReader(raw))
+	dec.Codec = codec
+	if _, err := dec.Next(); !errors.Is(err, ErrChecksum) {
+		FailWithError(t, "TestDecoderOffsetAfterFailure",
+			fmt.Errorf("expected ErrChecksum, got %v", err))
+	}
+	_, err := dec.Next()
+	var te *TLVError
+	if !errors.As(err, &te) {
+		FailWithError(t, "TestDecoderOffsetAfterFailure",
+			fmt.Errorf("expected a *TLVError, got %v", err))
+	} else if te.Offset != int64(len(raw)) {
+		FailWithError(t, "TestDecoderOffsetAfterFailure",
+			fmt.Errorf("expected offset %d, got %d", len(raw), te.Offset))
+	}
+}
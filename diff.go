@@ -0,0 +1,117 @@
+package tlv
+
+import "fmt"
+
+// ChangeKind says how an object differs between two TLVLists.
+type ChangeKind int
+
+const (
+	// ChangeAdded marks an object only in the second TLVList.
+	ChangeAdded ChangeKind = iota + 1
+	// ChangeRemoved marks an object only in the first TLVList.
+	ChangeRemoved
+	// ChangeModified marks an object whose value differs between the TLVLists.
+	ChangeModified
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	case ChangeModified:
+		return "modified"
+	}
+	return fmt.Sprintf("ChangeKind(%d)", int(k))
+}
+
+// Change is one difference found by Diff. Old is the object from the first TLVList and
+// New the object from the second; Old is nil for an addition and New is nil for a removal.
+type Change struct {
+	Kind ChangeKind
+	Old  TLV
+	New  TLV
+}
+
+// String formats the change as its kind followed by the objects involved.
+func (c Change) String() string {
+	switch c.Kind {
+	case ChangeAdded:
+		return fmt.Sprintf("+ type=0x%02x value=%x", TypeU32(c.New), c.New.Value())
+	case ChangeRemoved:
+		return fmt.Sprintf("- type=0x%02x value=%x", TypeU32(c.Old), c.Old.Value())
+	}
+	return fmt.Sprintf("~ type=0x%02x value=%x -> %x", TypeU32(c.Old), c.Old.Value(), c.New.Value())
+}
+
+// Diff returns the changes that turn TLVList a into b, in order, from a longest common
+// subsequence of their objects. Between two common objects, a removed and an added object
+// of the same type are reported as a single modification. A nil TLVList is treated as empty.
+func Diff(a, b *List) []Change {
+	var x, y []TLV
+	if a != nil {
+		x = a.ToSlice()
+	}
+	if b != nil {
+		y = b.ToSlice()
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of x[i:] and y[j:].
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if Equal(x[i], y[j]) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var changes []Change
+	var removed, added []TLV
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && Equal(x[i], y[j]):
+			changes = appendChanges(changes, removed, added)
+			removed, added = removed[:0], added[:0]
+			i++
+			j++
+		case j == len(y) || (i < len(x) && lcs[i+1][j] >= lcs[i][j+1]):
+			removed = append(removed, x[i])
+			i++
+		default:
+			added = append(added, y[j])
+			j++
+		}
+	}
+	return appendChanges(changes, removed, added)
+}
+
+// appendChanges appends the changes for a run of removed and added objects,
+// pairing each removed object with the first unpaired added object of its type.
+func appendChanges(changes []Change, removed, added []TLV) []Change {
+	paired := make([]bool, len(added))
+	for _, old := range removed {
+		c := Change{Kind: ChangeRemoved, Old: old}
+		for k, tlv := range added {
+			if !paired[k] && TypeU32(tlv) == TypeU32(old) {
+				c.Kind, c.New = ChangeModified, tlv
+				paired[k] = true
+				break
+			}
+		}
+		changes = append(changes, c)
+	}
+	for k, tlv := range added {
+		if !paired[k] {
+			changes = append(changes, Change{Kind: ChangeAdded, New: tlv})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,71 @@
+package tlv
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func formatChanges(changes []Change) string {
+	lines := make([]string, len(changes))
+	for i, c := range changes {
+		lines[i] = c.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestDiff(t *testing.T) {
+	a := NewList()
+	a.Add(TypeTest1, []byte("foo bar"))
+	a.Add(TypeTest2, []byte("baz quux"))
+	a.Add(TypeTest3, []byte("gophers are everywhere!"))
+	a.Add(TypeTest4, []byte{0x01})
+
+	tests := []struct {
+		name     string
+		edit     func(*List)
+		expected []Change
+	}{
+		{"equal", func(tl *List) {}, nil},
+		{"insertion", func(tl *List) {
+			tl.InsertBefore(TypeTest3, New(TypeTest5, []byte("goodbye, cruel world")))
+		}, []Change{
+			{Kind: ChangeAdded, New: New(TypeTest5, []byte("goodbye, cruel world"))},
+		}},
+		{"deletion", func(tl *List) {
+			tl.Remove(TypeTest2)
+		}, []Change{
+			{Kind: ChangeRemoved, Old: New(TypeTest2, []byte("baz quux"))},
+		}},
+		{"modification", func(tl *List) {
+			tl.Set(TypeTest3, []byte("goodbye, cruel world"))
+		}, []Change{
+			{Kind: ChangeModified, Old: New(TypeTest3, []byte("gophers are everywhere!")),
+				New: New(TypeTest3, []byte("goodbye, cruel world"))},
+		}},
+		{"mixed", func(tl *List) {
+			tl.RemoveFirst(TypeTest1)
+			tl.Set(TypeTest4, []byte{0x02})
+			tl.Add(TypeTest6, nil)
+		}, []Change{
+			{Kind: ChangeRemoved, Old: New(TypeTest1, []byte("foo bar"))},
+			{Kind: ChangeModified, Old: New(TypeTest4, []byte{0x01}), New: New(TypeTest4, []byte{0x02})},
+			{Kind: ChangeAdded, New: New(TypeTest6, nil)},
+		}},
+	}
+
+	for _, test := range tests {
+		b := a.Clone()
+		test.edit(b)
+		changes := Diff(a, b)
+		if got, want := formatChanges(changes), formatChanges(test.expected); got != want {
+			FailWithError(t, "TestDiff",
+				fmt.Errorf("%s: got\n%s\nexpected\n%s", test.name, got, want))
+		}
+	}
+
+	if changes := Diff(nil, a); len(changes) != 4 || changes[0].Kind != ChangeAdded {
+		FailWithError(t, "TestDiff",
+			fmt.Errorf("diff from nil list:\n%s", formatChanges(changes)))
+	}
+}
@@ -0,0 +1,44 @@
+package tlv
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// Encoder writes TLV objects one at a time to an output stream.
+type Encoder struct {
+	// Codec, if set, gives the layout objects are written in.
+	// The default layout is written otherwise.
+	Codec *Codec
+
+	w io.Writer
+}
+
+// NewEncoder returns a new Encoder that writes to w.
+// The Encoder doesn't buffer, each object is written to w as it is encoded.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Encode writes a TLV object to the stream in the Encoder's Codec layout.
+// Errors are as from Codec.WriteObject.
+func (e *Encoder) Encode(tlv TLV) error {
+	codec := e.Codec
+	if codec == nil {
+		codec = defaultCodec
+	}
+	return codec.WriteObject(tlv, e.w)
+}
+
+// WriteHeader writes the type and length of a TLV object, to be followed by length bytes of value.
+func WriteHeader(w io.Writer, typ byte, length int32) error {
+	if length < 0 {
+		return ErrLengthOverflow
+	}
+	var hdr [headerSize]byte
+	hdr[0] = typ
+	binary.BigEndian.PutUint32(hdr[1:], uint32(length))
+	fw := fullWriter{w: w}
+	_, err := fw.Write(hdr[:])
+	return fw.wrap(err)
+}
@@ -0,0 +1,83 @@
+package tlv
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestEncoderDecoder(t *testing.T) {
+	tlvs := []TLV{
+		New(TypeTest1, []byte("foo bar")),
+		New(TypeTest2, []byte("baz quux")),
+		New(TypeTest3, nil),
+		New(TypeTest4, []byte("gophers are everywhere!")),
+	}
+
+	buf := new(bytes.Buffer)
+	enc := NewEncoder(buf)
+	for _, tlv := range tlvs {
+		if err := enc.Encode(tlv); err != nil {
+			FailWithError(t, "TestEncoderDecoder", err)
+		}
+	}
+
+	tlvl := NewList()
+	for _, tlv := range tlvs {
+		tlvl.AddObject(tlv)
+	}
+	expected := new(bytes.Buffer)
+	if err := tlvl.Write(expected); err != nil {
+		FailWithError(t, "TestEncoderDecoder", err)
+	}
+	if !bytes.Equal(buf.Bytes(), expected.Bytes()) {
+		FailWithError(t, "TestEncoderDecoder",
+			fmt.Errorf("encoded bytes differ from List.Write"))
+	}
+
+	dec := NewDecoder(buf)
+	for _, tlv := range tlvs {
+		tmpTLV, err := dec.Next()
+		if err != nil {
+			FailWithError(t, "TestEncoderDecoder", err)
+		} else if !Equal(tlv, tmpTLV) {
+			FailWithError(t, "TestEncoderDecoder", errNoMatch)
+		}
+	}
+	if _, err := dec.Next(); err != io.EOF {
+		FailWithError(t, "TestEncoderDecoder",
+			fmt.Errorf("expected io.EOF, got %v", err))
+	}
+}
+
+func TestEncoderCodec(t *testing.T) {
+	codec := NewCodec16()
+	tlvs := []TLV{
+		NewTypeU32(0x1234, []byte("foo bar")),
+		New(TypeTest2, []byte("gophers are everywhere!")),
+	}
+
+	buf := new(bytes.Buffer)
+	enc := NewEncoder(buf)
+	enc.Codec = codec
+	for _, tlv := range tlvs {
+		if err := enc.Encode(tlv); err != nil {
+			FailWithError(t, "TestEncoderCodec", err)
+		}
+	}
+
+	dec := NewDecoder(buf)
+	dec.Codec = codec
+	for _, expected := range tlvs {
+		if tlv, err := dec.Next(); err != nil {
+			FailWithError(t, "TestEncoderCodec", err)
+		} else if !Equal(tlv, expected) {
+			FailWithError(t, "TestEncoderCodec", errNoMatch)
+		}
+	}
+	if _, err := dec.Next(); err != io.EOF {
+		FailWithError(t, "TestEncoderCodec",
+			fmt.Errorf("expected io.EOF, got %v", err))
+	}
+}
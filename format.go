@@ -0,0 +1,56 @@
+package tlv
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// String formats the object as type=0x01 len=4 value=0a0b0c0d.
+func (o *object) String() string {
+	return fmt.Sprintf("type=0x%02x len=%d value=%x", o.typ, o.len, o.val)
+}
+
+// String formats the TLVList with one object per line.
+func (tl *List) String() string {
+	lines := make([]string, 0, tl.Length())
+	for e := tl.objects.Front(); e != nil; e = e.Next() {
+		tlv := e.Value.(TLV)
+		lines = append(lines, fmt.Sprintf("type=0x%02x len=%d value=%x",
+			TypeU32(tlv), tlv.Length(), tlv.Value()))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Pretty formats the TLVList with one object per line, as Version(0x01): 0x0102,
+// naming each type from names. Types missing from names are shown by number only.
+func (tl *List) Pretty(names map[byte]string) string {
+	lines := make([]string, 0, tl.Length())
+	for e := tl.objects.Front(); e != nil; e = e.Next() {
+		tlv := e.Value.(TLV)
+		typ, narrow := narrowType(tlv)
+		if name, ok := names[typ]; ok && narrow {
+			lines = append(lines, fmt.Sprintf("%s(0x%02x): 0x%x", name, typ, tlv.Value()))
+		} else {
+			lines = append(lines, fmt.Sprintf("0x%02x: 0x%x", TypeU32(tlv), tlv.Value()))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Dump returns an annotated hex dump of the TLVList. Each object is introduced by its
+// index, its offset in the default encoding and its header, followed by a hex dump of its value.
+func Dump(tl *List) string {
+	var b strings.Builder
+	var offset int64
+	var i int
+	for e := tl.objects.Front(); e != nil; e = e.Next() {
+		tlv := e.Value.(TLV)
+		fmt.Fprintf(&b, "object %d offset=%d type=0x%02x len=%d\n",
+			i, offset, TypeU32(tlv), tlv.Length())
+		b.WriteString(hex.Dump(tlv.Value()))
+		offset += headerSize + int64(len(tlv.Value()))
+		i++
+	}
+	return b.String()
+}
@@ -0,0 +1,55 @@
+package tlv
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestTLVString(t *testing.T) {
+	tlv := New(TypeTest2, []byte{0x0a, 0x0b, 0x0c, 0x0d})
+	if s := fmt.Sprint(tlv); s != "type=0x01 len=4 value=0a0b0c0d" {
+		FailWithError(t, "TestTLVString",
+			fmt.Errorf("formatted %q", s))
+	}
+}
+
+func TestTLVListString(t *testing.T) {
+	tlvl := NewList()
+	tlvl.Add(TypeTest2, []byte{0x0a, 0x0b, 0x0c, 0x0d})
+	tlvl.Add(TypeTest3, nil)
+
+	expected := "type=0x01 len=4 value=0a0b0c0d\ntype=0x02 len=0 value="
+	if s := fmt.Sprint(tlvl); s != expected {
+		FailWithError(t, "TestTLVListString",
+			fmt.Errorf("formatted %q, expected %q", s, expected))
+	}
+}
+
+func TestDump(t *testing.T) {
+	tlvl := NewList()
+	tlvl.Add(TypeTest2, []byte{0x0a, 0x0b, 0x0c, 0x0d})
+	tlvl.Add(TypeTest3, []byte("foo"))
+
+	expected := "object 0 offset=0 type=0x01 len=4\n" +
+		"00000000  0a 0b 0c 0d                                       |....|\n" +
+		"object 1 offset=9 type=0x02 len=3\n" +
+		"00000000  66 6f 6f                                          |foo|\n"
+	if s := Dump(tlvl); s != expected {
+		FailWithError(t, "TestDump",
+			fmt.Errorf("dumped %q, expected %q", s, expected))
+	}
+}
+
+func TestTLVListPretty(t *testing.T) {
+	tlvl := NewList()
+	tlvl.AddObject(NewUint16(0x01, 0x0102))
+	tlvl.Add(0x02, []byte("foo"))
+	tlvl.Add(0x7f, []byte{0xff})
+
+	names := map[byte]string{0x01: "Version", 0x02: "Name"}
+	expected := "Version(0x01): 0x0102\nName(0x02): 0x666f6f\n0x7f: 0xff"
+	if s := tlvl.Pretty(names); s != expected {
+		FailWithError(t, "TestTLVListPretty",
+			fmt.Errorf("got\n%s\nexpected\n%s", s, expected))
+	}
+}
@@ -0,0 +1,34 @@
+package tlv
+
+// NewFragments splits val into ordered objects of the given type, each holding at most
+// maxChunk bytes of it. A value no longer than maxChunk, including an empty one, yields
+// a single object, as does a non-positive maxChunk.
+func NewFragments(typ byte, val []byte, maxChunk int) []TLV {
+	if maxChunk <= 0 || len(val) <= maxChunk {
+		return []TLV{New(typ, val)}
+	}
+
+	objs := make([]TLV, 0, (len(val)+maxChunk-1)/maxChunk)
+	for len(val) > 0 {
+		n := maxChunk
+		if n > len(val) {
+			n = len(val)
+		}
+		objs = append(objs, New(typ, val[:n]))
+		val = val[n:]
+	}
+	return objs
+}
+
+// Reassemble returns the concatenated values of the objects, in order.
+func Reassemble(objs []TLV) []byte {
+	var n int
+	for _, tlv := range objs {
+		n += len(tlv.Value())
+	}
+	val := make([]byte, 0, n)
+	for _, tlv := range objs {
+		val = append(val, tlv.Value()...)
+	}
+	return val
+}
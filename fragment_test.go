@@ -0,0 +1,46 @@
+package tlv
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestFragments(t *testing.T) {
+	payload := bytes.Repeat([]byte("gophers are everywhere!"), 10)
+	for _, size := range []int{0, 1, 15, 16, 17, 100, len(payload)} {
+		val := payload[:size]
+		objs := NewFragments(TypeTest1, val, 16)
+
+		expected := (size + 15) / 16
+		if expected == 0 {
+			expected = 1
+		}
+		if len(objs) != expected {
+			FailWithError(t, "TestFragments",
+				fmt.Errorf("size %d: %d fragments, expected %d", size, len(objs), expected))
+		}
+		for i, tlv := range objs {
+			if tlv.Type() != TypeTest1 || tlv.Length() > 16 {
+				FailWithError(t, "TestFragments",
+					fmt.Errorf("size %d: bad fragment %d: %s", size, i, tlv))
+			}
+		}
+
+		if out := Reassemble(objs); !bytes.Equal(out, val) {
+			FailWithError(t, "TestFragments",
+				fmt.Errorf("size %d: reassembled %q", size, out))
+		}
+	}
+}
+
+func TestFragmentsNonPositiveChunk(t *testing.T) {
+	val := []byte("gophers are everywhere!")
+	for _, maxChunk := range []int{0, -1} {
+		objs := NewFragments(TypeTest1, val, maxChunk)
+		if len(objs) != 1 || !bytes.Equal(objs[0].Value(), val) {
+			FailWithError(t, "TestFragmentsNonPositiveChunk",
+				fmt.Errorf("maxChunk %d: %d fragments, expected 1", maxChunk, len(objs)))
+		}
+	}
+}
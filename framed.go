@@ -0,0 +1,52 @@
+package tlv
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// ErrFrameLength is returned when a frame's objects don't end exactly at its declared length.
+var ErrFrameLength = fmt.Errorf("TLV %s", "frame length mismatch")
+
+// WriteFramed writes the TLVList to io.Writer as a frame: the total length of the objects
+// as 4 big-endian bytes, followed by the objects. The objects are encoded before anything
+// is written, so an object that can't be encoded leaves w untouched.
+func WriteFramed(tl *List, w io.Writer) error {
+	frame, err := tl.AppendTo(make([]byte, 4, 4+tl.Size()))
+	if err != nil {
+		return err
+	}
+	size := len(frame) - 4
+	if uint64(size) > math.MaxUint32 {
+		return ErrLengthOverflow
+	}
+
+	binary.BigEndian.PutUint32(frame, uint32(size))
+	fw := &fullWriter{w: w}
+	_, err = fw.Write(frame)
+	return fw.wrap(err)
+}
+
+// ReadFramed reads a frame written by WriteFramed, consuming exactly the prefix and the
+// declared number of bytes. It returns an error wrapping ErrFrameLength if the last object
+// runs past the end of the frame, and io.ErrUnexpectedEOF if the stream ends first.
+func ReadFramed(r io.Reader) (*List, error) {
+	var prefix [4]byte
+	if _, err := io.ReadFull(r, prefix[:]); err != nil {
+		return nil, err
+	}
+
+	lr := &io.LimitedReader{R: r, N: int64(binary.BigEndian.Uint32(prefix[:]))}
+	tl, err := Read(lr)
+	if err != nil && lr.N == 0 && errors.Is(err, io.ErrUnexpectedEOF) {
+		return tl, fmt.Errorf("%w: %v", ErrFrameLength, err)
+	} else if err != nil {
+		return tl, err
+	} else if lr.N != 0 {
+		return tl, io.ErrUnexpectedEOF
+	}
+	return tl, nil
+}
@@ -0,0 +1,91 @@
+package tlv
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestFramedRoundTrip(t *testing.T) {
+	tlvl := NewList()
+	tlvl.Add(TypeTest1, []byte("foo bar"))
+	tlvl.Add(TypeTest2, []byte("baz quux"))
+
+	buf := new(bytes.Buffer)
+	if err := WriteFramed(tlvl, buf); err != nil {
+		FailWithError(t, "TestFramedRoundTrip", err)
+	} else if n := binary.BigEndian.Uint32(buf.Bytes()); int(n) != tlvl.Size() {
+		FailWithError(t, "TestFramedRoundTrip",
+			fmt.Errorf("frame length %d, expected %d", n, tlvl.Size()))
+	}
+	buf.WriteString("trailer")
+
+	rtlvl, err := ReadFramed(buf)
+	if err != nil {
+		FailWithError(t, "TestFramedRoundTrip", err)
+	} else if !rtlvl.Equal(tlvl) {
+		FailWithError(t, "TestFramedRoundTrip", errNoMatch)
+	} else if buf.String() != "trailer" {
+		FailWithError(t, "TestFramedRoundTrip",
+			fmt.Errorf("%q left after frame, expected \"trailer\"", buf.String()))
+	}
+}
+
+func TestFramedLengthMismatch(t *testing.T) {
+	tlvl := NewList()
+	tlvl.Add(TypeTest1, []byte("foo bar"))
+	tlvl.Add(TypeTest2, []byte("baz quux"))
+	buf := new(bytes.Buffer)
+	if err := WriteFramed(tlvl, buf); err != nil {
+		FailWithError(t, "TestFramedLengthMismatch", err)
+	}
+	data := buf.Bytes()
+
+	short := append([]byte(nil), data...)
+	binary.BigEndian.PutUint32(short, uint32(tlvl.Size()-2))
+	if _, err := ReadFramed(bytes.NewReader(short)); !errors.Is(err, ErrFrameLength) {
+		FailWithError(t, "TestFramedLengthMismatch",
+			fmt.Errorf("expected ErrFrameLength, got %v", err))
+	}
+
+	long := append([]byte(nil), data...)
+	binary.BigEndian.PutUint32(long, uint32(tlvl.Size()+2))
+	if _, err := ReadFramed(bytes.NewReader(long)); err != io.ErrUnexpectedEOF {
+		FailWithError(t, "TestFramedLengthMismatch",
+			fmt.Errorf("expected io.ErrUnexpectedEOF, got %v", err))
+	}
+}
+
+func TestFramedInvalidObject(t *testing.T) {
+	tlvl := NewList()
+	tlvl.Add(TypeTest1, []byte("foo bar"))
+	tlvl.AddObject(NewTypeU32(0x1234, []byte("baz quux")))
+
+	buf := new(bytes.Buffer)
+	if err := WriteFramed(tlvl, buf); err != ErrTypeOverflow {
+		FailWithError(t, "TestFramedInvalidObject",
+			fmt.Errorf("expected ErrTypeOverflow, got %v", err))
+	} else if buf.Len() != 0 {
+		FailWithError(t, "TestFramedInvalidObject",
+			fmt.Errorf("%d bytes written for a frame that failed", buf.Len()))
+	}
+
+}
+
+func TestFramedWriteError(t *testing.T) {
+	tlvl := NewList()
+	tlvl.Add(TypeTest1, []byte("foo bar"))
+	cw := &chunkWriter{size: 3, limit: 6}
+	err := WriteFramed(tlvl, cw)
+	var te *TLVError
+	if !errors.As(err, &te) || !errors.Is(err, io.ErrClosedPipe) {
+		FailWithError(t, "TestFramedWriteError",
+			fmt.Errorf("expected a write *TLVError, got %v", err))
+	} else if te.Op != "write" || te.Offset != int64(cw.buf.Len()) {
+		FailWithError(t, "TestFramedWriteError",
+			fmt.Errorf("%s error at offset %d, expected write at %d", te.Op, te.Offset, cw.buf.Len()))
+	}
+}
@@ -0,0 +1,38 @@
+package tlv
+
+import (
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+)
+
+// Hash returns a 64-bit FNV-1a hash of the TLVList's objects, in order.
+// Each object contributes its full type, length and value, so Equal TLVLists hash the same.
+func (tl *List) Hash() uint64 {
+	h := fnv.New64a()
+	for e := tl.objects.Front(); e != nil; e = e.Next() {
+		hashObject(h, e.Value.(TLV))
+	}
+	return h.Sum64()
+}
+
+// HashUnordered returns a hash of the TLVList's objects that doesn't depend on their order,
+// so TLVLists that are EqualUnordered hash the same.
+func (tl *List) HashUnordered() uint64 {
+	var sum uint64
+	h := fnv.New64a()
+	for e := tl.objects.Front(); e != nil; e = e.Next() {
+		h.Reset()
+		hashObject(h, e.Value.(TLV))
+		sum += h.Sum64()
+	}
+	return sum
+}
+
+func hashObject(h hash.Hash64, tlv TLV) {
+	var hdr [8]byte
+	binary.BigEndian.PutUint32(hdr[:4], TypeU32(tlv))
+	binary.BigEndian.PutUint32(hdr[4:], uint32(len(tlv.Value())))
+	h.Write(hdr[:])
+	h.Write(tlv.Value())
+}
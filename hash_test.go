@@ -0,0 +1,37 @@
+package tlv
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestTLVListHash(t *testing.T) {
+	tlvl := NewList()
+	tlvl.Add(TypeTest1, []byte("foo bar"))
+	tlvl.Add(TypeTest2, []byte("baz quux"))
+	tlvl.Add(TypeTest1, []byte("goodbye, cruel world"))
+
+	clone := tlvl.Clone()
+	if tlvl.Hash() != clone.Hash() || tlvl.HashUnordered() != clone.HashUnordered() {
+		FailWithError(t, "TestTLVListHash", fmt.Errorf("equal lists hash differently"))
+	}
+
+	reordered := NewList()
+	reordered.Add(TypeTest1, []byte("goodbye, cruel world"))
+	reordered.Add(TypeTest2, []byte("baz quux"))
+	reordered.Add(TypeTest1, []byte("foo bar"))
+	if tlvl.Hash() == reordered.Hash() {
+		FailWithError(t, "TestTLVListHash", fmt.Errorf("reordered list has the same ordered hash"))
+	} else if tlvl.HashUnordered() != reordered.HashUnordered() {
+		FailWithError(t, "TestTLVListHash", fmt.Errorf("reordered list has a different unordered hash"))
+	}
+
+	// Moving bytes between adjacent values must change the hash.
+	shifted := NewList()
+	shifted.Add(TypeTest1, []byte("foo ba"))
+	shifted.Add(TypeTest2, []byte("rbaz quux"))
+	shifted.Add(TypeTest1, []byte("goodbye, cruel world"))
+	if tlvl.Hash() == shifted.Hash() || tlvl.HashUnordered() == shifted.HashUnordered() {
+		FailWithError(t, "TestTLVListHash", fmt.Errorf("different lists hash the same"))
+	}
+}
@@ -0,0 +1,27 @@
+//go:build go1.23
+
+package tlv
+
+import "iter"
+
+// All returns an iterator over the objects in the TLVList, in insertion order.
+func (tl *List) All() iter.Seq[TLV] {
+	return func(yield func(TLV) bool) {
+		for e := tl.objects.Front(); e != nil; e = e.Next() {
+			if !yield(e.Value.(TLV)) {
+				return
+			}
+		}
+	}
+}
+
+// Types returns an iterator over the type of each object in the TLVList, in insertion order.
+func (tl *List) Types() iter.Seq[byte] {
+	return func(yield func(byte) bool) {
+		for e := tl.objects.Front(); e != nil; e = e.Next() {
+			if !yield(e.Value.(TLV).Type()) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,56 @@
+//go:build go1.23
+
+package tlv
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestTLVListAll(t *testing.T) {
+	tlvs := []TLV{
+		New(TypeTest1, []byte("foo bar")),
+		New(TypeTest2, []byte("baz quux")),
+		New(TypeTest1, []byte("goodbye, cruel world")),
+	}
+	tlvl := NewList()
+	for _, tlv := range tlvs {
+		tlvl.AddObject(tlv)
+	}
+
+	var i int
+	for tlv := range tlvl.All() {
+		if i >= len(tlvs) {
+			FailWithError(t, "TestTLVListAll",
+				fmt.Errorf("too many records visited"))
+		} else if !Equal(tlv, tlvs[i]) {
+			FailWithError(t, "TestTLVListAll", errNoMatch)
+		}
+		i++
+	}
+	if i != len(tlvs) {
+		FailWithError(t, "TestTLVListAll",
+			fmt.Errorf("visited %d records, expected %d", i, len(tlvs)))
+	}
+
+	for range tlvl.All() {
+		break
+	}
+}
+
+func TestTLVListTypes(t *testing.T) {
+	tlvl := NewList()
+	tlvl.Add(TypeTest3, []byte("foo bar"))
+	tlvl.Add(TypeTest1, []byte("baz quux"))
+	tlvl.Add(TypeTest3, []byte("goodbye, cruel world"))
+
+	expected := []byte{TypeTest3, TypeTest1, TypeTest3}
+	var types []byte
+	for typ := range tlvl.Types() {
+		types = append(types, typ)
+	}
+	if string(types) != string(expected) {
+		FailWithError(t, "TestTLVListTypes",
+			fmt.Errorf("types %v, expected %v", types, expected))
+	}
+}
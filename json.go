@@ -0,0 +1,82 @@
+package tlv
+
+import (
+	"container/list"
+	"encoding/json"
+)
+
+// jsonObject is the JSON representation of a TLV object; encoding/json base64-encodes the value.
+type jsonObject struct {
+	Type   uint32 `json:"type"`
+	Length *int32 `json:"length,omitempty"`
+	Value  []byte `json:"value"`
+}
+
+func toJSONObject(tlv TLV) jsonObject {
+	length := tlv.Length()
+	val := tlv.Value()
+	if val == nil {
+		val = []byte{}
+	}
+	return jsonObject{Type: TypeU32(tlv), Length: &length, Value: val}
+}
+
+func (jo jsonObject) object() (*object, error) {
+	if jo.Length != nil && int(*jo.Length) != len(jo.Value) {
+		return nil, ErrInvalidLength
+	}
+	return NewTypeU32(jo.Type, jo.Value).(*object), nil
+}
+
+// MarshalJSON encodes the object as {"type":1,"length":4,"value":"<base64>"}.
+func (o *object) MarshalJSON() ([]byte, error) {
+	return json.Marshal(toJSONObject(o))
+}
+
+// UnmarshalJSON decodes an object encoded by MarshalJSON. The length may be omitted,
+// but if present it must match the decoded value.
+func (o *object) UnmarshalJSON(data []byte) error {
+	var jo jsonObject
+	if err := json.Unmarshal(data, &jo); err != nil {
+		return err
+	}
+	obj, err := jo.object()
+	if err != nil {
+		return err
+	}
+	*o = *obj
+	return nil
+}
+
+// MarshalJSON encodes the TLVList as a JSON array of objects, in order.
+func (tl *List) MarshalJSON() ([]byte, error) {
+	objs := make([]jsonObject, 0, tl.Length())
+	for e := tl.objects.Front(); e != nil; e = e.Next() {
+		objs = append(objs, toJSONObject(e.Value.(TLV)))
+	}
+	return json.Marshal(objs)
+}
+
+// UnmarshalJSON decodes a JSON array of objects and appends them to the TLVList, in order.
+func (tl *List) UnmarshalJSON(data []byte) error {
+	var objs []jsonObject
+	if err := json.Unmarshal(data, &objs); err != nil {
+		return err
+	}
+	decoded := make([]TLV, 0, len(objs))
+	for _, jo := range objs {
+		obj, err := jo.object()
+		if err != nil {
+			return err
+		}
+		decoded = append(decoded, obj)
+	}
+
+	if tl.objects == nil {
+		tl.objects = list.New()
+	}
+	for _, obj := range decoded {
+		tl.objects.PushBack(obj)
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package tlv
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestJSONObject(t *testing.T) {
+	tlv := New(TypeTest2, []byte{0x0a, 0x0b, 0x0c, 0x0d})
+	data, err := json.Marshal(tlv)
+	if err != nil {
+		FailWithError(t, "TestJSONObject", err)
+	}
+	if expected := `{"type":1,"length":4,"value":"CgsMDQ=="}`; string(data) != expected {
+		FailWithError(t, "TestJSONObject",
+			fmt.Errorf("encoded %s, expected %s", data, expected))
+	}
+
+	tmpTLV := new(object)
+	if err := json.Unmarshal(data, tmpTLV); err != nil {
+		FailWithError(t, "TestJSONObject", err)
+	} else if !Equal(tlv, tmpTLV) {
+		FailWithError(t, "TestJSONObject", errNoMatch)
+	}
+
+	if err := json.Unmarshal([]byte(`{"type":1,"length":5,"value":"CgsMDQ=="}`), tmpTLV); err != ErrInvalidLength {
+		FailWithError(t, "TestJSONObject",
+			fmt.Errorf("expected ErrInvalidLength, got %v", err))
+	}
+}
+
+func TestJSONList(t *testing.T) {
+	tlvs := []TLV{
+		New(TypeTest1, []byte("foo bar")),
+		New(TypeTest2, nil),
+		New(TypeTest1, []byte("goodbye, cruel world")),
+	}
+	tlvl := NewList()
+	for _, tlv := range tlvs {
+		tlvl.AddObject(tlv)
+	}
+
+	data, err := json.Marshal(tlvl)
+	if err != nil {
+		FailWithError(t, "TestJSONList", err)
+	}
+
+	var rtlvl List
+	if err := json.Unmarshal(data, &rtlvl); err != nil {
+		FailWithError(t, "TestJSONList", err)
+	}
+	if rtlvl.Length() != int32(len(tlvs)) {
+		FailWithError(t, "TestJSONList",
+			fmt.Errorf("%d records decoded, expected %d", rtlvl.Length(), len(tlvs)))
+	}
+	var i int
+	rtlvl.Each(func(tlv TLV) bool {
+		if !Equal(tlv, tlvs[i]) {
+			FailWithError(t, "TestJSONList", errNoMatch)
+		}
+		i++
+		return true
+	})
+}
+
+func TestJSONListOrder(t *testing.T) {
+	data := []byte(`[
+		{"type": 3, "value": "Z29waGVycw=="},
+		{"type": 1, "value": "Zm9vIGJhcg=="},
+		{"type": 2, "length": 0, "value": ""}
+	]`)
+
+	tlvl := NewList()
+	tlvl.Add(TypeTest6, nil)
+	if err := json.Unmarshal(data, tlvl); err != nil {
+		FailWithError(t, "TestJSONListOrder", err)
+	}
+
+	expected := NewList()
+	expected.Add(TypeTest6, nil)
+	expected.Add(3, []byte("gophers"))
+	expected.Add(1, []byte("foo bar"))
+	expected.Add(2, nil)
+	if !tlvl.Equal(expected) {
+		FailWithError(t, "TestJSONListOrder",
+			fmt.Errorf("got\n%s\nexpected\n%s", tlvl, expected))
+	}
+}
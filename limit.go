@@ -0,0 +1,86 @@
+package tlv
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+var (
+	// ErrObjectLimit is returned when a stream holds more objects than allowed.
+	ErrObjectLimit = fmt.Errorf("TLV %s", "object limit exceeded")
+	// ErrByteLimit is returned when a stream is longer than allowed.
+	ErrByteLimit = fmt.Errorf("TLV %s", "byte limit exceeded")
+)
+
+// ReadLimited builds a TLVList from io.Reader like Read, but fails with ErrObjectLimit once
+// the stream holds more than maxObjects objects, or ErrByteLimit once it is longer than maxBytes.
+// A non-positive limit is not checked. On error the objects read so far are returned.
+func ReadLimited(r io.Reader, maxObjects int, maxBytes int64) (*List, error) {
+	tl := NewList()
+	if maxBytes <= 0 {
+		_, err := defaultCodec.readInto(tl, r, readHooks{after: objectLimit(maxObjects)})
+		return tl, err
+	}
+
+	// One byte of slack tells a stream ending exactly at the limit from one running past it.
+	lr := &io.LimitedReader{R: r, N: maxBytes + 1}
+	codec := new(Codec)
+	checkObjects := objectLimit(maxObjects)
+	_, err := codec.readInto(tl, lr, readHooks{
+		before: func(int) error {
+			// A declared length beyond the remaining budget can't fit, so don't allocate it.
+			codec.MaxValueLength = int32(min(lr.N, math.MaxInt32))
+			return nil
+		},
+		after: func(n int, tlv TLV) (bool, bool, error) {
+			if lr.N == 0 {
+				return false, false, ErrByteLimit
+			}
+			return checkObjects(n, tlv)
+		},
+	})
+	if err != nil && (lr.N == 0 || errors.Is(err, ErrValueTooLarge)) {
+		return tl, ErrByteLimit
+	}
+	return tl, err
+}
+
+// objectLimit returns a readHooks.after failing with ErrObjectLimit past maxObjects objects.
+func objectLimit(maxObjects int) func(int, TLV) (bool, bool, error) {
+	return func(n int, _ TLV) (bool, bool, error) {
+		if maxObjects > 0 && n == maxObjects {
+			return false, false, ErrObjectLimit
+		}
+		return true, false, nil
+	}
+}
+
+// ReadObjectBudget reads a TLV object from io.Reader like ReadObject, but never consumes
+// more than budget bytes of r. It returns ErrByteLimit if the whole object, as declared by
+// its header, doesn't fit in the budget; a declared length that can't fit is rejected
+// before its value is read.
+func ReadObjectBudget(r io.Reader, budget int64) (TLV, error) {
+	if budget <= 0 {
+		return nil, ErrByteLimit
+	}
+
+	lr := &io.LimitedReader{R: r, N: budget}
+	var hdr [headerSize]byte
+	typ, length, err := readHeader(lr, &hdr)
+	if lr.N == 0 && errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, ErrByteLimit
+	} else if err != nil {
+		return nil, err
+	} else if int64(length) > lr.N {
+		// Checked against what is left of the budget before the value is allocated.
+		return nil, ErrByteLimit
+	}
+
+	tlv := &object{typ: uint32(typ), len: length, val: make([]byte, length)}
+	if err := readValue(lr, tlv.val); err != nil {
+		return nil, err
+	}
+	return tlv, nil
+}
@@ -0,0 +1,138 @@
+package tlv
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func limitTestData(t *testing.T) ([]byte, *List) {
+	tlvl := NewList()
+	tlvl.Add(TypeTest1, []byte("foo bar"))
+	tlvl.Add(TypeTest2, []byte("baz quux"))
+	tlvl.Add(TypeTest3, []byte("gophers are everywhere!"))
+	buf := new(bytes.Buffer)
+	if err := tlvl.Write(buf); err != nil {
+		FailWithError(t, "limitTestData", err)
+	}
+	return buf.Bytes(), tlvl
+}
+
+func TestReadLimited(t *testing.T) {
+	data, tlvl := limitTestData(t)
+
+	rtlvl, err := ReadLimited(bytes.NewReader(data), 3, int64(len(data)))
+	if err != nil {
+		FailWithError(t, "TestReadLimited", err)
+	} else if !rtlvl.Equal(tlvl) {
+		FailWithError(t, "TestReadLimited", errNoMatch)
+	}
+
+	rtlvl, err = ReadLimited(bytes.NewReader(data), 0, 0)
+	if err != nil {
+		FailWithError(t, "TestReadLimited", err)
+	} else if !rtlvl.Equal(tlvl) {
+		FailWithError(t, "TestReadLimited", errNoMatch)
+	}
+}
+
+func TestReadLimitedObjects(t *testing.T) {
+	data, _ := limitTestData(t)
+
+	rtlvl, err := ReadLimited(bytes.NewReader(data), 2, 0)
+	if err != ErrObjectLimit {
+		FailWithError(t, "TestReadLimitedObjects",
+			fmt.Errorf("expected ErrObjectLimit, got %v", err))
+	} else if rtlvl.Length() != 2 {
+		FailWithError(t, "TestReadLimitedObjects",
+			fmt.Errorf("%d records read, expected 2", rtlvl.Length()))
+	}
+}
+
+func TestReadLimitedBytes(t *testing.T) {
+	data, _ := limitTestData(t)
+
+	rtlvl, err := ReadLimited(bytes.NewReader(data), 0, int64(len(data)-1))
+	if err != ErrByteLimit {
+		FailWithError(t, "TestReadLimitedBytes",
+			fmt.Errorf("expected ErrByteLimit, got %v", err))
+	} else if rtlvl.Length() != 2 {
+		FailWithError(t, "TestReadLimitedBytes",
+			fmt.Errorf("%d records read, expected 2", rtlvl.Length()))
+	}
+
+	huge := []byte{TypeTest1, 0x7f, 0xff, 0xff, 0xff}
+	if _, err := ReadLimited(bytes.NewReader(huge), 0, 1024); err != ErrByteLimit {
+		FailWithError(t, "TestReadLimitedBytes",
+			fmt.Errorf("expected ErrByteLimit, got %v", err))
+	}
+}
+
+func TestReadObjectBudget(t *testing.T) {
+	data, _ := limitTestData(t)
+
+	// The first object is a 5-byte header and 7 bytes of value.
+	tlv, err := ReadObjectBudget(bytes.NewReader(data), 12)
+	if err != nil {
+		FailWithError(t, "TestReadObjectBudget", err)
+	} else if !Equal(tlv, New(TypeTest1, []byte("foo bar"))) {
+		FailWithError(t, "TestReadObjectBudget", errNoMatch)
+	}
+
+	r := bytes.NewReader(data)
+	if _, err = ReadObjectBudget(r, 11); err != ErrByteLimit {
+		FailWithError(t, "TestReadObjectBudget",
+			fmt.Errorf("expected ErrByteLimit, got %v", err))
+	} else if consumed := len(data) - r.Len(); consumed > 11 {
+		FailWithError(t, "TestReadObjectBudget",
+			fmt.Errorf("%d bytes consumed, budget was 11", consumed))
+	}
+
+	huge := append([]byte{TypeTest1, 0x7f, 0xff, 0xff, 0xff}, data...)
+	r = bytes.NewReader(huge)
+	if _, err = ReadObjectBudget(r, 1024); err != ErrByteLimit {
+		FailWithError(t, "TestReadObjectBudget",
+			fmt.Errorf("expected ErrByteLimit, got %v", err))
+	} else if consumed := len(huge) - r.Len(); consumed != headerSize {
+		FailWithError(t, "TestReadObjectBudget",
+			fmt.Errorf("%d bytes consumed, expected only the header", consumed))
+	}
+}
+
+func TestReadObjectBudgetHeaderOnly(t *testing.T) {
+	hostile := []byte{TypeTest1, 0x7f, 0xff, 0xff, 0xff}
+	if _, err := ReadObjectBudget(bytes.NewReader(hostile), headerSize); err != ErrByteLimit {
+		FailWithError(t, "TestReadObjectBudgetHeaderOnly",
+			fmt.Errorf("expected ErrByteLimit, got %v", err))
+	}
+
+	empty := []byte{TypeTest1, 0, 0, 0, 0}
+	if tlv, err := ReadObjectBudget(bytes.NewReader(empty), headerSize); err != nil {
+		FailWithError(t, "TestReadObjectBudgetHeaderOnly", err)
+	} else if !Equal(tlv, New(TypeTest1, nil)) {
+		FailWithError(t, "TestReadObjectBudgetHeaderOnly", errNoMatch)
+	}
+
+	if _, err := ReadObjectBudget(bytes.NewReader(hostile), 4); err != ErrByteLimit {
+		FailWithError(t, "TestReadObjectBudgetHeaderOnly",
+			fmt.Errorf("partial header: expected ErrByteLimit, got %v", err))
+	}
+}
+
+func TestReadLimitedOffset(t *testing.T) {
+	data, _ := limitTestData(t)
+	raw := data[:len(data)-1]
+
+	for _, maxBytes := range []int64{0, int64(len(data))} {
+		_, err := ReadLimited(bytes.NewReader(raw), 0, maxBytes)
+		var te *TLVError
+		if !errors.As(err, &te) {
+			FailWithError(t, "TestReadLimitedOffset",
+				fmt.Errorf("limit %d: expected a *TLVError, got %v", maxBytes, err))
+		} else if te.Offset != int64(len(raw)) {
+			FailWithError(t, "TestReadLimitedOffset",
+				fmt.Errorf("limit %d: expected offset %d, got %d", maxBytes, len(raw), te.Offset))
+		}
+	}
+}
@@ -0,0 +1,259 @@
+package tlv
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// Marshaler is implemented by types that encode themselves into a TLV value.
+type Marshaler interface {
+	MarshalTLV() ([]byte, error)
+}
+
+// Unmarshaler is implemented by types that decode themselves from a TLV value.
+type Unmarshaler interface {
+	UnmarshalTLV([]byte) error
+}
+
+// ErrInvalidUnmarshal is returned when Unmarshal is not given a non-nil pointer to a struct.
+var ErrInvalidUnmarshal = fmt.Errorf("TLV %s", "unmarshal requires a non-nil struct pointer")
+
+var (
+	marshalerType   = reflect.TypeOf((*Marshaler)(nil)).Elem()
+	unmarshalerType = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+)
+
+// Marshal returns the TLV encoding of v, which must be a struct or a pointer to one.
+//
+// Each exported field tagged like `tlv:"1"` is written as an object of that type.
+// Integers are encoded big-endian at their natural size, int and uint as 8 bytes,
+// bools as a single byte, strings and byte slices verbatim, and nested structs as
+// constructed objects whose value is the struct's own encoding.
+// Fields without a tag, or tagged `tlv:"-"`, are ignored.
+func Marshal(v any) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, fmt.Errorf("TLV marshal: nil %s", rv.Type())
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("TLV marshal: unsupported type %s", rv.Type())
+	} else if !rv.CanAddr() {
+		// Copy the struct so fields with pointer-receiver MarshalTLV methods can be addressed.
+		addr := reflect.New(rv.Type()).Elem()
+		addr.Set(rv)
+		rv = addr
+	}
+
+	tl, err := marshalStruct(rv)
+	if err != nil {
+		return nil, err
+	}
+	buf := new(bytes.Buffer)
+	err = tl.Write(buf)
+	return buf.Bytes(), err
+}
+
+// Unmarshal parses the TLV encoded data and stores the result in the struct pointed to by v.
+// It uses the same field tags as Marshal. Objects whose type has no matching field are skipped,
+// and fields with no matching object keep their current value.
+func Unmarshal(data []byte, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return ErrInvalidUnmarshal
+	}
+
+	tl, err := Read(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	return unmarshalStruct(tl, rv.Elem())
+}
+
+// fieldType returns the TLV type for a struct field, and false if the field should be ignored.
+func fieldType(f reflect.StructField) (byte, bool, error) {
+	tag, ok := f.Tag.Lookup("tlv")
+	if !ok || tag == "-" || f.PkgPath != "" {
+		return 0, false, nil
+	}
+	typ, err := strconv.ParseUint(tag, 0, 8)
+	if err != nil {
+		return 0, false, fmt.Errorf("TLV field %s: invalid tag %q", f.Name, tag)
+	}
+	return byte(typ), true, nil
+}
+
+func marshalStruct(rv reflect.Value) (*List, error) {
+	tl := NewList()
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		typ, ok, err := fieldType(rt.Field(i))
+		if err != nil {
+			return nil, err
+		} else if !ok {
+			continue
+		}
+
+		fv := rv.Field(i)
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				continue
+			}
+			if !fv.Type().Implements(marshalerType) {
+				fv = fv.Elem()
+			}
+		}
+
+		if fv.Kind() == reflect.Struct && !isMarshaler(fv) {
+			children, err := marshalStruct(fv)
+			if err != nil {
+				return nil, err
+			}
+			nested, err := NewNested(typ, children)
+			if err != nil {
+				return nil, fmt.Errorf("TLV field %s: %w", rt.Field(i).Name, err)
+			}
+			tl.AddObject(nested)
+			continue
+		}
+
+		val, err := marshalValue(fv)
+		if err != nil {
+			return nil, fmt.Errorf("TLV field %s: %w", rt.Field(i).Name, err)
+		}
+		tl.Add(typ, val)
+	}
+	return tl, nil
+}
+
+// isMarshaler reports whether fv, or a pointer to it, implements Marshaler.
+func isMarshaler(fv reflect.Value) bool {
+	return fv.Type().Implements(marshalerType) || (fv.CanAddr() && fv.Addr().Type().Implements(marshalerType))
+}
+
+func marshalValue(fv reflect.Value) ([]byte, error) {
+	if fv.Type().Implements(marshalerType) {
+		return fv.Interface().(Marshaler).MarshalTLV()
+	} else if fv.CanAddr() && fv.Addr().Type().Implements(marshalerType) {
+		return fv.Addr().Interface().(Marshaler).MarshalTLV()
+	}
+
+	switch fv.Kind() {
+	case reflect.Bool:
+		if fv.Bool() {
+			return []byte{1}, nil
+		}
+		return []byte{0}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		val := make([]byte, intSize(fv.Type()))
+		putUint(val, binary.BigEndian, uint64(fv.Int()))
+		return val, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		val := make([]byte, intSize(fv.Type()))
+		putUint(val, binary.BigEndian, fv.Uint())
+		return val, nil
+	case reflect.String:
+		return []byte(fv.String()), nil
+	case reflect.Slice:
+		if fv.Type().Elem().Kind() == reflect.Uint8 {
+			return fv.Bytes(), nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported type %s", fv.Type())
+}
+
+func unmarshalStruct(tl *List, rv reflect.Value) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		typ, ok, err := fieldType(rt.Field(i))
+		if err != nil {
+			return err
+		} else if !ok {
+			continue
+		}
+
+		tlv, err := tl.Get(typ)
+		if err == ErrTypeNotFound {
+			continue
+		}
+
+		fv := rv.Field(i)
+		if fv.Kind() == reflect.Ptr {
+			if fv.IsNil() {
+				fv.Set(reflect.New(fv.Type().Elem()))
+			}
+			if !fv.Type().Implements(unmarshalerType) {
+				fv = fv.Elem()
+			}
+		}
+
+		if fv.Kind() == reflect.Struct && !fv.Addr().Type().Implements(unmarshalerType) {
+			children, err := Children(tlv)
+			if err != nil {
+				return fmt.Errorf("TLV field %s: %w", rt.Field(i).Name, err)
+			}
+			if err := unmarshalStruct(children, fv); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := unmarshalValue(tlv.Value(), fv); err != nil {
+			return fmt.Errorf("TLV field %s: %w", rt.Field(i).Name, err)
+		}
+	}
+	return nil
+}
+
+func unmarshalValue(val []byte, fv reflect.Value) error {
+	if fv.Type().Implements(unmarshalerType) {
+		return fv.Interface().(Unmarshaler).UnmarshalTLV(append([]byte(nil), val...))
+	} else if fv.CanAddr() && fv.Addr().Type().Implements(unmarshalerType) {
+		return fv.Addr().Interface().(Unmarshaler).UnmarshalTLV(append([]byte(nil), val...))
+	}
+
+	switch fv.Kind() {
+	case reflect.Bool:
+		if len(val) != 1 {
+			return ErrInvalidLength
+		}
+		fv.SetBool(val[0] != 0)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if len(val) != intSize(fv.Type()) {
+			return ErrInvalidLength
+		}
+		// Sign-extend by shifting the value into the top bits and back.
+		shift := 64 - 8*uint(len(val))
+		fv.SetInt(int64(getUint(val, binary.BigEndian)<<shift) >> shift)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if len(val) != intSize(fv.Type()) {
+			return ErrInvalidLength
+		}
+		fv.SetUint(getUint(val, binary.BigEndian))
+		return nil
+	case reflect.String:
+		fv.SetString(string(val))
+		return nil
+	case reflect.Slice:
+		if fv.Type().Elem().Kind() == reflect.Uint8 {
+			fv.SetBytes(append([]byte(nil), val...))
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported type %s", fv.Type())
+}
+
+// intSize returns the encoded size of an integer type; int and uint always use 8 bytes.
+func intSize(t reflect.Type) int {
+	if t.Kind() == reflect.Int || t.Kind() == reflect.Uint {
+		return 8
+	}
+	return int(t.Size())
+}
@@ -0,0 +1,137 @@
+package tlv
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+type marshalInner struct {
+	Name  string `tlv:"1"`
+	Count uint16 `tlv:"2"`
+}
+
+type marshalOuter struct {
+	ID      uint32       `tlv:"1"`
+	Offset  int64        `tlv:"2"`
+	Delta   int8         `tlv:"3"`
+	Enabled bool         `tlv:"4"`
+	Label   string       `tlv:"5"`
+	Raw     []byte       `tlv:"6"`
+	Inner   marshalInner `tlv:"0x27"`
+	Ignored string
+	Skipped string `tlv:"-"`
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	in := marshalOuter{
+		ID:      0xdeadbeef,
+		Offset:  -42,
+		Delta:   -1,
+		Enabled: true,
+		Label:   "foo bar",
+		Raw:     []byte{0, 1, 2, 3},
+		Inner:   marshalInner{Name: "baz quux", Count: 7},
+		Ignored: "not encoded",
+		Skipped: "not encoded",
+	}
+
+	data, err := Marshal(&in)
+	if err != nil {
+		FailWithError(t, "TestMarshalRoundTrip", err)
+	}
+
+	tl, err := Read(bytes.NewReader(data))
+	if err != nil {
+		FailWithError(t, "TestMarshalRoundTrip", err)
+	}
+	if tl.Length() != 7 {
+		FailWithError(t, "TestMarshalRoundTrip",
+			fmt.Errorf("%d records encoded, expected 7", tl.Length()))
+	}
+	if tlv, err := tl.Get(1); err != nil {
+		FailWithError(t, "TestMarshalRoundTrip", err)
+	} else if !Equal(tlv, NewUint32(1, 0xdeadbeef)) {
+		FailWithError(t, "TestMarshalRoundTrip", errNoMatch)
+	}
+
+	var out marshalOuter
+	if err := Unmarshal(data, &out); err != nil {
+		FailWithError(t, "TestMarshalRoundTrip", err)
+	}
+	in.Ignored, in.Skipped = "", ""
+	if out.ID != in.ID || out.Offset != in.Offset || out.Delta != in.Delta ||
+		out.Enabled != in.Enabled || out.Label != in.Label ||
+		!bytes.Equal(out.Raw, in.Raw) || out.Inner != in.Inner ||
+		out.Ignored != "" || out.Skipped != "" {
+		FailWithError(t, "TestMarshalRoundTrip",
+			fmt.Errorf("decoded %+v, expected %+v", out, in))
+	}
+}
+
+func TestUnmarshalSkipsUnknown(t *testing.T) {
+	tl := NewList()
+	tl.AddObject(NewString(1, "foo bar"))
+	tl.Add(TypeTest6, []byte("unknown"))
+	tl.AddObject(NewUint16(2, 3))
+	buf := new(bytes.Buffer)
+	if err := tl.Write(buf); err != nil {
+		FailWithError(t, "TestUnmarshalSkipsUnknown", err)
+	}
+
+	var out marshalInner
+	if err := Unmarshal(buf.Bytes(), &out); err != nil {
+		FailWithError(t, "TestUnmarshalSkipsUnknown", err)
+	}
+	if out.Name != "foo bar" || out.Count != 3 {
+		FailWithError(t, "TestUnmarshalSkipsUnknown",
+			fmt.Errorf("decoded %+v", out))
+	}
+
+	if err := Unmarshal(buf.Bytes(), out); err != ErrInvalidUnmarshal {
+		FailWithError(t, "TestUnmarshalSkipsUnknown",
+			fmt.Errorf("expected ErrInvalidUnmarshal, got %v", err))
+	}
+}
+
+// marshalPoint encodes itself as two bytes through pointer-receiver methods.
+type marshalPoint struct {
+	X, Y byte
+}
+
+func (p *marshalPoint) MarshalTLV() ([]byte, error) {
+	return []byte{p.X, p.Y}, nil
+}
+
+func (p *marshalPoint) UnmarshalTLV(val []byte) error {
+	if len(val) != 2 {
+		return ErrInvalidLength
+	}
+	p.X, p.Y = val[0], val[1]
+	return nil
+}
+
+type marshalShape struct {
+	M marshalPoint `tlv:"1"`
+}
+
+func TestMarshalPointerReceiver(t *testing.T) {
+	in := marshalShape{M: marshalPoint{X: 3, Y: 4}}
+	for _, v := range []any{in, &in} {
+		data, err := Marshal(v)
+		if err != nil {
+			FailWithError(t, "TestMarshalPointerReceiver", err)
+		} else if !bytes.Equal(data, []byte{1, 0, 0, 0, 2, 3, 4}) {
+			FailWithError(t, "TestMarshalPointerReceiver",
+				fmt.Errorf("marshaled % x", data))
+		}
+
+		var out marshalShape
+		if err := Unmarshal(data, &out); err != nil {
+			FailWithError(t, "TestMarshalPointerReceiver", err)
+		} else if out != in {
+			FailWithError(t, "TestMarshalPointerReceiver",
+				fmt.Errorf("unmarshaled %+v, expected %+v", out, in))
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package tlv
+
+import (
+	"bytes"
+	"io"
+)
+
+// IsConstructed reports whether the object's type has the BER constructed bit, 0x20, set,
+// marking an object whose value is itself a sequence of TLV objects.
+func IsConstructed(tlv TLV) bool {
+	return IsConstructedMask(tlv, 0x20)
+}
+
+// IsConstructedMask reports whether the object's type has any of the mask bits set,
+// for formats that mark constructed objects with bits other than BER's.
+func IsConstructedMask(tlv TLV, mask byte) bool {
+	return tlv.Type()&mask != 0
+}
+
+// BER type byte classes, as returned by Class.
+const (
+	ClassUniversal   byte = 0
+	ClassApplication byte = 1
+	ClassContext     byte = 2
+	ClassPrivate     byte = 3
+)
+
+// Class returns the class held in the top two bits of the object's type byte.
+func Class(tlv TLV) byte {
+	return tlv.Type() >> 6
+}
+
+// TagNumber returns the tag number held in the low five bits of the object's type byte.
+func TagNumber(tlv TLV) byte {
+	return tlv.Type() & 0x1f
+}
+
+// NewNested returns a TLV object whose value is the serialized children.
+// Children must fit the default layout, as written by WriteObject; the first child
+// that doesn't fails with the error from AppendTo.
+func NewNested(typ byte, children *List) (TLV, error) {
+	val, err := children.AppendTo(nil)
+	if err != nil {
+		return nil, err
+	}
+	return New(typ, val), nil
+}
+
+// Children parses the value of a TLV object as a sequence of child objects.
+func Children(tlv TLV) (*List, error) {
+	return Read(bytes.NewReader(tlv.Value()))
+}
+
+// ReadNested reads a TLV object from io.Reader and parses its value as a sequence of child objects.
+func ReadNested(r io.Reader) (*List, error) {
+	tlv, err := ReadObject(r)
+	if err != nil {
+		return nil, err
+	}
+	return Children(tlv)
+}
+
+// Walk visits tlv and, depth-first in order, every object nested within it, descending
+// into the objects IsConstructed reports. Visit is called with the types from tlv down to
+// the visited object, inclusive; the path is reused, so copy it to keep it after visit returns.
+// An error from visit, or from parsing a constructed value, stops the walk and is returned.
+func Walk(tlv TLV, visit func(path []byte, tlv TLV) error) error {
+	return WalkWith(tlv, IsConstructed, visit)
+}
+
+// WalkWith is like Walk, but descends into the objects the constructed predicate reports.
+func WalkWith(tlv TLV, constructed func(TLV) bool, visit func(path []byte, tlv TLV) error) error {
+	return walk(nil, tlv, constructed, visit)
+}
+
+func walk(path []byte, tlv TLV, constructed func(TLV) bool, visit func([]byte, TLV) error) error {
+	path = append(path, tlv.Type())
+	if err := visit(path, tlv); err != nil {
+		return err
+	} else if !constructed(tlv) {
+		return nil
+	}
+
+	children, err := Children(tlv)
+	if err != nil {
+		return err
+	}
+	for e := children.objects.Front(); e != nil; e = e.Next() {
+		if err := walk(path, e.Value.(TLV), constructed, visit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package tlv
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestNestedRoundTrip(t *testing.T) {
+	inner := NewList()
+	inner.Add(TypeTest3, []byte("gophers are everywhere!"))
+	inner.Add(TypeTest4, []byte("baz quux"))
+
+	outer := NewList()
+	outer.Add(TypeTest1, []byte("foo bar"))
+	child, err := NewNested(TypeTest2|0x20, inner)
+	if err != nil {
+		FailWithError(t, "TestNestedRoundTrip", err)
+	}
+	outer.AddObject(child)
+	outer.Add(TypeTest1, []byte("goodbye, cruel world"))
+
+	parent, err := NewNested(TypeTest5|0x20, outer)
+	if err != nil {
+		FailWithError(t, "TestNestedRoundTrip", err)
+	} else if !IsConstructed(parent) {
+		FailWithError(t, "TestNestedRoundTrip",
+			fmt.Errorf("parent should be constructed"))
+	}
+
+	data, err := ToBytes(parent)
+	if err != nil {
+		FailWithError(t, "TestNestedRoundTrip", err)
+	}
+
+	children, err := ReadNested(bytes.NewReader(data))
+	if err != nil {
+		FailWithError(t, "TestNestedRoundTrip", err)
+	}
+	if children.Length() != 3 {
+		FailWithError(t, "TestNestedRoundTrip",
+			fmt.Errorf("%d children, expected 3", children.Length()))
+	}
+
+	expected := []TLV{
+		New(TypeTest1, []byte("foo bar")),
+		nil,
+		New(TypeTest1, []byte("goodbye, cruel world")),
+	}
+	var i int
+	for e := children.objects.Front(); e != nil; e = e.Next() {
+		child := e.Value.(TLV)
+		if i == 1 {
+			if !IsConstructed(child) {
+				FailWithError(t, "TestNestedRoundTrip",
+					fmt.Errorf("child %d should be constructed", i))
+			}
+			grandchildren, err := Children(child)
+			if err != nil {
+				FailWithError(t, "TestNestedRoundTrip", err)
+			}
+			tmpTLV, err := grandchildren.Get(TypeTest3)
+			if err != nil {
+				FailWithError(t, "TestNestedRoundTrip", err)
+			} else if !Equal(tmpTLV, New(TypeTest3, []byte("gophers are everywhere!"))) {
+				FailWithError(t, "TestNestedRoundTrip", errNoMatch)
+			}
+			if grandchildren.objects.Back().Value.(TLV).Type() != TypeTest4 {
+				FailWithError(t, "TestNestedRoundTrip",
+					fmt.Errorf("grandchildren out of order"))
+			}
+		} else if !Equal(child, expected[i]) {
+			FailWithError(t, "TestNestedRoundTrip", errNoMatch)
+		}
+		i++
+	}
+}
+
+func TestIsConstructed(t *testing.T) {
+	if IsConstructed(New(TypeTest1, nil)) {
+		FailWithError(t, "TestIsConstructed",
+			fmt.Errorf("primitive type reported as constructed"))
+	}
+
+	if !IsConstructed(New(0x20|TypeTest2, nil)) {
+		FailWithError(t, "TestIsConstructed",
+			fmt.Errorf("constructed type reported as primitive"))
+	}
+
+	if !IsConstructedMask(New(TypeTest2, nil), 0x01) {
+		FailWithError(t, "TestIsConstructed",
+			fmt.Errorf("custom mask not honored"))
+	} else if IsConstructed(New(TypeTest2, nil)) {
+		FailWithError(t, "TestIsConstructed",
+			fmt.Errorf("custom mask changed IsConstructed"))
+	}
+}
+
+func TestClassAndTagNumber(t *testing.T) {
+	classes := []byte{ClassUniversal, ClassApplication, ClassContext, ClassPrivate}
+	for _, class := range classes {
+		for _, constructed := range []bool{false, true} {
+			typ := class<<6 | 0x11
+			if constructed {
+				typ |= 0x20
+			}
+			tlv := New(typ, nil)
+			if Class(tlv) != class {
+				FailWithError(t, "TestClassAndTagNumber",
+					fmt.Errorf("type 0x%02x: class %d, expected %d", typ, Class(tlv), class))
+			}
+			if IsConstructed(tlv) != constructed {
+				FailWithError(t, "TestClassAndTagNumber",
+					fmt.Errorf("type 0x%02x: constructed %v, expected %v", typ, IsConstructed(tlv), constructed))
+			}
+			if TagNumber(tlv) != 0x11 {
+				FailWithError(t, "TestClassAndTagNumber",
+					fmt.Errorf("type 0x%02x: tag number 0x%02x, expected 0x11", typ, TagNumber(tlv)))
+			}
+		}
+	}
+}
+
+func TestWalk(t *testing.T) {
+	inner := NewList()
+	inner.Add(TypeTest3, []byte("gophers are everywhere!"))
+	inner.Add(TypeTest4, []byte("baz quux"))
+
+	outer := NewList()
+	outer.Add(TypeTest1, []byte("foo bar"))
+	child, err := NewNested(TypeTest2|0x20, inner)
+	if err != nil {
+		FailWithError(t, "TestWalk", err)
+	}
+	outer.AddObject(child)
+	outer.Add(TypeTest5, []byte("goodbye, cruel world"))
+	root, err := NewNested(TypeTest6|0x20, outer)
+	if err != nil {
+		FailWithError(t, "TestWalk", err)
+	}
+
+	var paths []string
+	err = Walk(root, func(path []byte, tlv TLV) error {
+		paths = append(paths, fmt.Sprintf("%x", path))
+		return nil
+	})
+	if err != nil {
+		FailWithError(t, "TestWalk", err)
+	}
+
+	expected := []string{"25", "2500", "2521", "252102", "252103", "2504"}
+	if fmt.Sprint(paths) != fmt.Sprint(expected) {
+		FailWithError(t, "TestWalk",
+			fmt.Errorf("visited %v, expected %v", paths, expected))
+	}
+
+	stop := fmt.Errorf("stop")
+	paths = nil
+	err = Walk(root, func(path []byte, tlv TLV) error {
+		paths = append(paths, fmt.Sprintf("%x", path))
+		if tlv.Type() == TypeTest3 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop {
+		FailWithError(t, "TestWalk", fmt.Errorf("walk returned %v, expected stop", err))
+	} else if len(paths) != 4 {
+		FailWithError(t, "TestWalk", fmt.Errorf("visited %v after stopping", paths))
+	}
+
+	var n int
+	err = WalkWith(root, func(tlv TLV) bool { return tlv.Type() == TypeTest6|0x20 },
+		func(path []byte, tlv TLV) error {
+			n++
+			return nil
+		})
+	if err != nil {
+		FailWithError(t, "TestWalk", err)
+	} else if n != 4 {
+		FailWithError(t, "TestWalk", fmt.Errorf("visited %d objects, expected 4", n))
+	}
+}
+
+func TestNewNestedInvalidChild(t *testing.T) {
+	children := NewList()
+	children.Add(TypeTest1, []byte("foo bar"))
+	children.AddObject(NewTypeU32(0x1234, []byte("baz quux")))
+	children.Add(TypeTest2, []byte("goodbye, cruel world"))
+
+	if parent, err := NewNested(TypeTest3|0x20, children); err != ErrTypeOverflow {
+		FailWithError(t, "TestNewNestedInvalidChild",
+			fmt.Errorf("expected ErrTypeOverflow, got %v", err))
+	} else if parent != nil {
+		FailWithError(t, "TestNewNestedInvalidChild",
+			fmt.Errorf("parent %s returned with an error", parent))
+	}
+}
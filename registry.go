@@ -0,0 +1,43 @@
+package tlv
+
+import "fmt"
+
+// ErrNoDecoder is returned by a strict Registry when no decoder is registered for a type.
+var ErrNoDecoder = fmt.Errorf("TLV %s", "no decoder registered")
+
+// Registry maps TLV types to decoders that turn values into Go values.
+type Registry struct {
+	// Strict makes Decode fail with ErrNoDecoder for unregistered types,
+	// instead of returning a copy of the raw value.
+	Strict bool
+
+	decoders map[byte]func([]byte) (any, error)
+}
+
+// NewRegistry returns a new, empty Registry.
+// The zero value is also an empty Registry ready to use.
+func NewRegistry() *Registry {
+	return new(Registry)
+}
+
+// Register sets the decoder for a type, replacing any previous one.
+func (r *Registry) Register(typ byte, fn func([]byte) (any, error)) {
+	if r.decoders == nil {
+		r.decoders = make(map[byte]func([]byte) (any, error))
+	}
+	r.decoders[typ] = fn
+}
+
+// Decode decodes a TLV object's value with the decoder registered for its type.
+// Unregistered types return a copy of the value as a []byte, or an error wrapping
+// ErrNoDecoder if the Registry is strict.
+func (r *Registry) Decode(tlv TLV) (any, error) {
+	typ, narrow := narrowType(tlv)
+	fn, ok := r.decoders[typ]
+	if ok && narrow {
+		return fn(tlv.Value())
+	} else if r.Strict {
+		return nil, fmt.Errorf("%w: 0x%02x", ErrNoDecoder, TypeU32(tlv))
+	}
+	return ValueCopy(tlv), nil
+}
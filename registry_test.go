@@ -0,0 +1,80 @@
+package tlv
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func testRegistry() *Registry {
+	reg := NewRegistry()
+	reg.Register(TypeTest1, func(val []byte) (any, error) {
+		return Uint32(New(TypeTest1, val))
+	})
+	reg.Register(TypeTest2, func(val []byte) (any, error) {
+		return string(val), nil
+	})
+	return reg
+}
+
+func TestRegistryDecode(t *testing.T) {
+	reg := testRegistry()
+	tlvl := NewList()
+	tlvl.AddObject(NewUint32(TypeTest1, 42))
+	tlvl.AddObject(NewString(TypeTest2, "foo bar"))
+	tlvl.Add(TypeTest3, []byte("raw"))
+
+	var decoded []any
+	tlvl.Each(func(tlv TLV) bool {
+		v, err := reg.Decode(tlv)
+		if err != nil {
+			FailWithError(t, "TestRegistryDecode", err)
+		}
+		decoded = append(decoded, v)
+		return true
+	})
+
+	if v, ok := decoded[0].(uint32); !ok || v != 42 {
+		FailWithError(t, "TestRegistryDecode",
+			fmt.Errorf("decoded %#v, expected uint32 42", decoded[0]))
+	}
+	if v, ok := decoded[1].(string); !ok || v != "foo bar" {
+		FailWithError(t, "TestRegistryDecode",
+			fmt.Errorf("decoded %#v, expected \"foo bar\"", decoded[1]))
+	}
+	if v, ok := decoded[2].([]byte); !ok || !bytes.Equal(v, []byte("raw")) {
+		FailWithError(t, "TestRegistryDecode",
+			fmt.Errorf("decoded %#v, expected raw bytes", decoded[2]))
+	}
+
+	if _, err := reg.Decode(New(TypeTest1, []byte{1})); err != ErrInvalidLength {
+		FailWithError(t, "TestRegistryDecode",
+			fmt.Errorf("expected ErrInvalidLength, got %v", err))
+	}
+}
+
+func TestRegistryStrict(t *testing.T) {
+	reg := testRegistry()
+	reg.Strict = true
+	if _, err := reg.Decode(New(TypeTest3, []byte("raw"))); !errors.Is(err, ErrNoDecoder) {
+		FailWithError(t, "TestRegistryStrict",
+			fmt.Errorf("expected ErrNoDecoder, got %v", err))
+	}
+}
+
+func TestRegistryZeroValue(t *testing.T) {
+	reg := &Registry{Strict: true}
+	if _, err := reg.Decode(New(TypeTest1, []byte("raw"))); !errors.Is(err, ErrNoDecoder) {
+		FailWithError(t, "TestRegistryZeroValue",
+			fmt.Errorf("expected ErrNoDecoder, got %v", err))
+	}
+
+	reg.Register(TypeTest1, func(val []byte) (any, error) { return string(val), nil })
+	if v, err := reg.Decode(New(TypeTest1, []byte("foo bar"))); err != nil {
+		FailWithError(t, "TestRegistryZeroValue", err)
+	} else if v != "foo bar" {
+		FailWithError(t, "TestRegistryZeroValue",
+			fmt.Errorf("decoded %#v, expected \"foo bar\"", v))
+	}
+}
@@ -0,0 +1,36 @@
+package tlv
+
+import "fmt"
+
+var (
+	// ErrMissingType is returned when a required type is absent from a TLVList.
+	ErrMissingType = fmt.Errorf("TLV %s", "required type missing")
+	// ErrRepeatedType is returned when a non-repeatable type appears more than once in a TLVList.
+	ErrRepeatedType = fmt.Errorf("TLV %s", "type repeated")
+)
+
+// SchemaEntry describes how a single type may appear in a TLVList.
+type SchemaEntry struct {
+	Type       byte
+	Required   bool
+	Repeatable bool
+}
+
+// Schema describes the types a TLVList is expected to contain.
+// Types without an entry are not checked.
+type Schema []SchemaEntry
+
+// Validate checks a TLVList against the schema. It returns an error wrapping
+// ErrMissingType or ErrRepeatedType for the first offending type.
+func (s Schema) Validate(tl *List) error {
+	h := tl.Histogram()
+	for _, entry := range s {
+		n := h[entry.Type]
+		if entry.Required && n == 0 {
+			return fmt.Errorf("%w: 0x%02x", ErrMissingType, entry.Type)
+		} else if !entry.Repeatable && n > 1 {
+			return fmt.Errorf("%w: 0x%02x", ErrRepeatedType, entry.Type)
+		}
+	}
+	return nil
+}
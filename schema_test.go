@@ -0,0 +1,56 @@
+package tlv
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+var testSchema = Schema{
+	{Type: TypeTest1, Required: true},
+	{Type: TypeTest2, Repeatable: true},
+	{Type: TypeTest3},
+}
+
+func TestSchemaValidate(t *testing.T) {
+	tlvl := NewList()
+	tlvl.Add(TypeTest1, []byte("foo bar"))
+	tlvl.Add(TypeTest2, []byte("baz quux"))
+	tlvl.Add(TypeTest2, []byte("goodbye, cruel world"))
+	tlvl.Add(TypeTest6, []byte("unknown"))
+
+	if err := testSchema.Validate(tlvl); err != nil {
+		FailWithError(t, "TestSchemaValidate", err)
+	}
+}
+
+func TestSchemaValidateMissing(t *testing.T) {
+	tlvl := NewList()
+	tlvl.Add(TypeTest2, []byte("baz quux"))
+
+	err := testSchema.Validate(tlvl)
+	if !errors.Is(err, ErrMissingType) {
+		FailWithError(t, "TestSchemaValidateMissing",
+			fmt.Errorf("expected ErrMissingType, got %v", err))
+	} else if !strings.Contains(err.Error(), "0x00") {
+		FailWithError(t, "TestSchemaValidateMissing",
+			fmt.Errorf("error %q does not name the type", err))
+	}
+}
+
+func TestSchemaValidateRepeated(t *testing.T) {
+	tlvl := NewList()
+	tlvl.Add(TypeTest1, []byte("foo bar"))
+	tlvl.Add(TypeTest3, []byte("gophers are everywhere!"))
+	tlvl.Add(TypeTest3, []byte("gophers are everywhere!"))
+
+	err := testSchema.Validate(tlvl)
+	if !errors.Is(err, ErrRepeatedType) {
+		FailWithError(t, "TestSchemaValidateRepeated",
+			fmt.Errorf("expected ErrRepeatedType, got %v", err))
+	} else if !strings.Contains(err.Error(), "0x02") {
+		FailWithError(t, "TestSchemaValidateRepeated",
+			fmt.Errorf("error %q does not name the type", err))
+	}
+}
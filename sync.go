@@ -0,0 +1,66 @@
+package tlv
+
+import (
+	"io"
+	"sync"
+)
+
+// SyncList is a TLVList that is safe for concurrent use by multiple goroutines.
+type SyncList struct {
+	mu sync.RWMutex
+	tl *List
+}
+
+// NewSyncList returns a new, empty SyncList.
+func NewSyncList() *SyncList {
+	return &SyncList{tl: NewList()}
+}
+
+// Length returns the number of objects in the SyncList.
+func (sl *SyncList) Length() int32 {
+	sl.mu.RLock()
+	defer sl.mu.RUnlock()
+	return sl.tl.Length()
+}
+
+// Get returns the first object matching the type, or ErrTypeNotFound.
+func (sl *SyncList) Get(typ byte) (TLV, error) {
+	sl.mu.RLock()
+	defer sl.mu.RUnlock()
+	return sl.tl.Get(typ)
+}
+
+// GetAll returns a slice containing all objects matching the type.
+func (sl *SyncList) GetAll(typ byte) []TLV {
+	sl.mu.RLock()
+	defer sl.mu.RUnlock()
+	return sl.tl.GetAll(typ)
+}
+
+// Remove removes all objects with the requested type, returning the number removed.
+func (sl *SyncList) Remove(typ byte) int {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	return sl.tl.Remove(typ)
+}
+
+// Add pushes a new TLV object onto the SyncList. It builds the object from its args
+func (sl *SyncList) Add(typ byte, value []byte) {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	sl.tl.Add(typ, value)
+}
+
+// AddObject adds a TLV object onto the SyncList
+func (sl *SyncList) AddObject(obj TLV) {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	sl.tl.AddObject(obj)
+}
+
+// Write writes out the SyncList to an io.Writer.
+func (sl *SyncList) Write(w io.Writer) error {
+	sl.mu.RLock()
+	defer sl.mu.RUnlock()
+	return sl.tl.Write(w)
+}
@@ -0,0 +1,51 @@
+package tlv
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sync"
+	"testing"
+)
+
+func TestSyncListConcurrent(t *testing.T) {
+	sl := NewSyncList()
+	sl.Add(TypeTest1, []byte("foo bar"))
+
+	const workers = 8
+	const rounds = 100
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < rounds; j++ {
+				sl.Add(TypeTest2, []byte("baz quux"))
+				sl.AddObject(New(TypeTest3, []byte("gophers are everywhere!")))
+				sl.Remove(TypeTest3)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < rounds; j++ {
+				if _, err := sl.Get(TypeTest1); err != nil {
+					t.Error(err)
+				}
+				sl.GetAll(TypeTest2)
+				sl.Length()
+				if err := sl.Write(ioutil.Discard); err != nil {
+					t.Error(err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := len(sl.GetAll(TypeTest2)); n != workers*rounds {
+		FailWithError(t, "TestSyncListConcurrent",
+			fmt.Errorf("%d TypeTest2 records, expected %d", n, workers*rounds))
+	}
+	if _, err := sl.Get(TypeTest3); err != ErrTypeNotFound {
+		FailWithError(t, "TestSyncListConcurrent",
+			fmt.Errorf("TypeTest3 records should be removed"))
+	}
+}
@@ -5,8 +5,12 @@ import (
 	"bytes"
 	"container/list"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
+	"math"
+	"slices"
+	"sort"
 )
 
 // TLV represents a Type-Length-Value object.
@@ -17,13 +21,18 @@ type TLV interface {
 }
 
 type object struct {
-	typ byte
+	typ uint32
 	len int32
 	val []byte
 }
 
 // Type returns the object's type
 func (o *object) Type() byte {
+	return byte(o.typ)
+}
+
+// TypeU32 returns the object's full type, for codecs with a type wider than one byte
+func (o *object) TypeU32() uint32 {
 	return o.typ
 }
 
@@ -32,18 +41,43 @@ func (o *object) Length() int32 {
 	return o.len
 }
 
-// Type returns the object's value
+// Value returns the object's value.
+// The returned slice is shared with the object, use ValueCopy to get an independent copy.
 func (o *object) Value() []byte {
 	return o.val
 }
 
+// MarshalBinary returns the object's encoding, as written by WriteObject.
+// It implements encoding.BinaryMarshaler.
+func (o *object) MarshalBinary() ([]byte, error) {
+	return ToBytes(o)
+}
+
+// UnmarshalBinary replaces the object with one decoded from data, as read by ReadObject.
+// It implements encoding.BinaryUnmarshaler.
+func (o *object) UnmarshalBinary(data []byte) error {
+	tlv, err := FromBytes(data)
+	if err != nil {
+		return err
+	}
+	*o = *tlv.(*object)
+	return nil
+}
+
+// ValueCopy returns a copy of a TLV object's value that is safe to store or modify
+func ValueCopy(tlv TLV) []byte {
+	val := make([]byte, len(tlv.Value()))
+	copy(val, tlv.Value())
+	return val
+}
+
 // Equal returns true if a pair of TLV objects are the same.
 func Equal(tlv1, tlv2 TLV) bool {
 	if tlv1 == nil {
 		return tlv2 == nil
 	} else if tlv2 == nil {
 		return false
-	} else if tlv1.Type() != tlv2.Type() {
+	} else if TypeU32(tlv1) != TypeU32(tlv2) {
 		return false
 	} else if tlv1.Length() != tlv2.Length() {
 		return false
@@ -53,6 +87,47 @@ func Equal(tlv1, tlv2 TLV) bool {
 	return true
 }
 
+// EqualByValue returns true if a pair of TLV objects have the same type and value bytes,
+// ignoring their Length, which may be wrong for a malformed object.
+func EqualByValue(tlv1, tlv2 TLV) bool {
+	if tlv1 == nil || tlv2 == nil {
+		return tlv1 == tlv2
+	}
+	return TypeU32(tlv1) == TypeU32(tlv2) && bytes.Equal(tlv1.Value(), tlv2.Value())
+}
+
+// EqualTrimmed returns true if a pair of TLV objects have the same type, and the same
+// value once trailing zero bytes are trimmed from both.
+func EqualTrimmed(tlv1, tlv2 TLV) bool {
+	if tlv1 == nil || tlv2 == nil {
+		return tlv1 == tlv2
+	} else if TypeU32(tlv1) != TypeU32(tlv2) {
+		return false
+	}
+	return bytes.Equal(bytes.TrimRight(tlv1.Value(), "\x00"), bytes.TrimRight(tlv2.Value(), "\x00"))
+}
+
+// TypeU32 returns the full type of a TLV object.
+// Objects that don't provide a TypeU32 method report their one-byte Type.
+func TypeU32(tlv TLV) uint32 {
+	if w, ok := tlv.(interface{ TypeU32() uint32 }); ok {
+		return w.TypeU32()
+	}
+	return uint32(tlv.Type())
+}
+
+// hasType reports whether the object's full type is the one-byte typ, so a byte query
+// never matches a wide type that merely shares its low byte.
+func hasType(tlv TLV, typ byte) bool {
+	return TypeU32(tlv) == uint32(typ)
+}
+
+// narrowType returns the object's type and true if it fits in one byte.
+func narrowType(tlv TLV) (byte, bool) {
+	typ := TypeU32(tlv)
+	return byte(typ), typ <= 0xff
+}
+
 var (
 	// ErrTLVRead is returned when there is an error reading a TLV object.
 	ErrTLVRead = fmt.Errorf("TLV %s", "read error")
@@ -60,10 +135,85 @@ var (
 	ErrTLVWrite = fmt.Errorf("TLV %s", "write error")
 	// ErrTypeNotFound is returned when a request for a TLV type is made and none can be found.
 	ErrTypeNotFound = fmt.Errorf("TLV %s", "type not found")
+	// ErrInvalidWidth is returned when a codec is configured with an unsupported field width.
+	ErrInvalidWidth = fmt.Errorf("TLV %s", "invalid field width")
+	// ErrTypeOverflow is returned when a TLV type does not fit in the codec's type width.
+	ErrTypeOverflow = fmt.Errorf("TLV %s", "type overflows type width")
+	// ErrLengthOverflow is returned when a TLV length does not fit in the codec's length width,
+	// or a decoded length does not fit in an int32.
+	ErrLengthOverflow = fmt.Errorf("TLV %s", "length overflows length width")
+	// ErrValueTooLarge is returned when a decoded length exceeds the codec's MaxValueLength.
+	ErrValueTooLarge = fmt.Errorf("TLV %s", "value too large")
+	// ErrInvalidLength is returned when a value doesn't have the length required to decode it.
+	ErrInvalidLength = fmt.Errorf("TLV %s", "invalid value length")
+	// ErrDuplicateType is returned when adding a type that must be unique and is already present.
+	ErrDuplicateType = fmt.Errorf("TLV %s", "duplicate type")
+	// ErrChecksum is returned when a value doesn't match its checksum trailer.
+	ErrChecksum = fmt.Errorf("TLV %s", "checksum mismatch")
+	// ErrTypeNotAllowed is returned when constructing an object with a type outside the allowed set.
+	ErrTypeNotAllowed = fmt.Errorf("TLV %s", "type not allowed")
+	// ErrTerminator is returned when an indefinite-length object can't be written with the codec's terminator.
+	ErrTerminator = fmt.Errorf("TLV %s", "invalid terminator")
+	// ErrIndexOutOfRange is returned when a position is outside the TLVList.
+	ErrIndexOutOfRange = fmt.Errorf("TLV %s", "index out of range")
 )
 
-// New returns a TLV object from the args
+// TLVError records a failed operation on a TLV stream, and the offset at which it failed.
+type TLVError struct {
+	Op     string
+	Offset int64
+	Err    error
+}
+
+func (e *TLVError) Error() string {
+	return fmt.Sprintf("TLV %s at offset %d: %v", e.Op, e.Offset, e.Err)
+}
+
+// Unwrap returns the underlying cause.
+func (e *TLVError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the error matches ErrTLVRead or ErrTLVWrite for read and write
+// operations, so callers checking for those sentinels keep working.
+func (e *TLVError) Is(target error) bool {
+	return (target == ErrTLVRead && e.Op == "read") || (target == ErrTLVWrite && e.Op == "write")
+}
+
+// shiftOffset adds start to the offset of a *TLVError in err's chain, making an offset
+// within an object relative to the stream the object started start bytes into.
+func shiftOffset(err error, start int64) {
+	var te *TLVError
+	if errors.As(err, &te) {
+		te.Offset += start
+	}
+}
+
+// headerSize is the size of a type byte followed by a 4-byte length.
+const headerSize = 5
+
+// Size returns the number of bytes WriteObject writes for a TLV object.
+func Size(tlv TLV) int {
+	return headerSize + len(tlv.Value())
+}
+
+// New returns a TLV object from the args.
+// A nil or empty val gives a zero-length object whose Value is an empty, non-nil slice.
 func New(typ byte, val []byte) TLV {
+	return NewTypeU32(uint32(typ), val)
+}
+
+// NewChecked returns a TLV object from the args if its type is in the allowed set.
+// Otherwise it returns an error wrapping ErrTypeNotAllowed.
+func NewChecked(typ byte, val []byte, allowed map[byte]bool) (TLV, error) {
+	if !allowed[typ] {
+		return nil, fmt.Errorf("%w: 0x%02x", ErrTypeNotAllowed, typ)
+	}
+	return New(typ, val), nil
+}
+
+// NewTypeU32 returns a TLV object with a type wider than one byte
+func NewTypeU32(typ uint32, val []byte) TLV {
 	tlv := new(object)
 	tlv.typ = typ
 	tlv.len = int32(len(val))
@@ -72,12 +222,58 @@ func New(typ byte, val []byte) TLV {
 	return tlv
 }
 
+// Clone returns a copy of a TLV object with its own value buffer
+func Clone(tlv TLV) TLV {
+	if tlv == nil {
+		return nil
+	}
+	return NewTypeU32(TypeU32(tlv), tlv.Value())
+}
+
+// Validate checks that a TLV object's Length matches the length of its Value.
+// Otherwise it returns an error wrapping ErrInvalidLength.
+func Validate(tlv TLV) error {
+	if int(tlv.Length()) != len(tlv.Value()) {
+		return fmt.Errorf("%w: length %d, value of %d bytes", ErrInvalidLength, tlv.Length(), len(tlv.Value()))
+	}
+	return nil
+}
+
+// Normalize returns a TLV object whose Length matches the length of its Value.
+// A valid object is returned as is; otherwise a copy is made, keeping the type and value.
+func Normalize(tlv TLV) TLV {
+	if Validate(tlv) == nil {
+		return tlv
+	}
+	return NewTypeU32(TypeU32(tlv), tlv.Value())
+}
+
+// SetValue returns a copy of a TLV object, keeping its type, holding val instead.
+// The length is taken from val, and ErrLengthOverflow is returned if it doesn't fit an int32.
+func SetValue(tlv TLV, val []byte) (TLV, error) {
+	if int64(len(val)) > math.MaxInt32 {
+		return nil, ErrLengthOverflow
+	}
+	return NewTypeU32(TypeU32(tlv), val), nil
+}
+
 // FromBytes returns a TLV object from bytes
 func FromBytes(data []byte) (TLV, error) {
 	objBuf := bytes.NewBuffer(data)
 	return ReadObject(objBuf)
 }
 
+// FromBytesN returns the TLV object at the start of data and the number of bytes it took,
+// so parsing can continue from data[n:].
+func FromBytesN(data []byte) (TLV, int, error) {
+	r := bytes.NewReader(data)
+	tlv, err := ReadObject(r)
+	if err != nil {
+		return nil, 0, err
+	}
+	return tlv, len(data) - r.Len(), nil
+}
+
 // ToBytes returns bytes from a TLV object
 func ToBytes(tlv TLV) ([]byte, error) {
 	data := make([]byte, 0)
@@ -86,65 +282,45 @@ func ToBytes(tlv TLV) ([]byte, error) {
 	return objBuf.Bytes(), err
 }
 
-// ReadObject returns a TLV object from io.Reader
-func ReadObject(r io.Reader) (TLV, error) {
-	tlv := new(object)
-
-	var typ byte
-	var err error
-	err = binary.Read(r, binary.BigEndian, &typ)
-	if err != nil {
-		return nil, err
+// AppendEncoded appends the bytes WriteObject would write for a TLV object to dst,
+// and returns the extended slice. Like WriteObject, it fails with ErrTypeOverflow for a
+// type wider than one byte and refuses an invalid object, returning dst unchanged.
+func AppendEncoded(dst []byte, tlv TLV) ([]byte, error) {
+	if TypeU32(tlv) > 0xff {
+		return dst, ErrTypeOverflow
+	} else if tlv.Length() < 0 {
+		return dst, ErrLengthOverflow
+	} else if err := Validate(tlv); err != nil {
+		return dst, err
 	}
-	tlv.typ = typ
 
-	var length int32
-	err = binary.Read(r, binary.BigEndian, &length)
-	if err != nil {
-		return nil, err
-	}
-	tlv.len = length
-
-	tlv.val = make([]byte, tlv.Length())
-	l, err := r.Read(tlv.val)
-	if err != nil {
-		return nil, err
-	} else if int32(l) != tlv.Length() {
-		return tlv, ErrTLVRead
-	}
+	dst = append(dst, tlv.Type())
+	dst = binary.BigEndian.AppendUint32(dst, uint32(len(tlv.Value())))
+	return append(dst, tlv.Value()...), nil
+}
 
-	return tlv, nil
+// ReadObject returns a TLV object from io.Reader
+func ReadObject(r io.Reader) (TLV, error) {
+	return defaultCodec.ReadObject(r)
 }
 
 // WriteObject writes a TLV object to io.Writer
 func WriteObject(tlv TLV, w io.Writer) error {
-	var err error
-
-	typ := tlv.Type()
-	err = binary.Write(w, binary.BigEndian, typ)
-	if err != nil {
-		return err
-	}
-
-	length := tlv.Length()
-	err = binary.Write(w, binary.BigEndian, length)
-	if err != nil {
-		return err
-	}
-
-	n, err := w.Write(tlv.Value())
-	if err != nil {
-		return err
-	} else if int32(n) != tlv.Length() {
-		return ErrTLVWrite
-	}
+	return defaultCodec.WriteObject(tlv, w)
+}
 
-	return nil
+// WriteObjectN writes a TLV object to io.Writer, returning the number of bytes written
+// including the header.
+func WriteObjectN(tlv TLV, w io.Writer) (int, error) {
+	cw := &countingWriter{w: w}
+	err := WriteObject(tlv, cw)
+	return int(cw.n), err
 }
 
 // List is ad double-linked list containing TLV objects.
 type List struct {
-	objects *list.List
+	objects  *list.List
+	validate func(typ byte, val []byte) error
 }
 
 // NewList returns a new, empty TLVList.
@@ -154,34 +330,301 @@ func NewList() *List {
 	return tl
 }
 
+// ListFromSlice returns a new TLVList holding the objects of a slice, in order.
+func ListFromSlice(objs []TLV) *List {
+	tl := NewList()
+	for _, obj := range objs {
+		tl.objects.PushBack(obj)
+	}
+	return tl
+}
+
+// ToSlice returns the objects of the TLVList as a slice, in order.
+func (tl *List) ToSlice() []TLV {
+	objs := make([]TLV, 0, tl.objects.Len())
+	for e := tl.objects.Front(); e != nil; e = e.Next() {
+		objs = append(objs, e.Value.(TLV))
+	}
+	return objs
+}
+
+// Clone returns a deep copy of the TLVList, cloning every object.
+// The clone keeps the TLVList's validator.
+func (tl *List) Clone() *List {
+	clone := NewList()
+	clone.validate = tl.validate
+	for e := tl.objects.Front(); e != nil; e = e.Next() {
+		clone.objects.PushBack(Clone(e.Value.(TLV)))
+	}
+	return clone
+}
+
+// Equal returns true if both TLVLists hold equal objects in the same order.
+// A nil TLVList is only equal to another nil TLVList.
+func (tl *List) Equal(other *List) bool {
+	if tl == nil || other == nil {
+		return tl == other
+	} else if tl.Length() != other.Length() {
+		return false
+	}
+	for e, o := tl.objects.Front(), other.objects.Front(); e != nil; e, o = e.Next(), o.Next() {
+		if !Equal(e.Value.(TLV), o.Value.(TLV)) {
+			return false
+		}
+	}
+	return true
+}
+
+// EqualUnordered returns true if both TLVLists hold the same objects, regardless of order.
+// Repeated objects must appear the same number of times in each.
+func (tl *List) EqualUnordered(other *List) bool {
+	if tl == nil || other == nil {
+		return tl == other
+	} else if tl.Length() != other.Length() {
+		return false
+	}
+
+	type key struct {
+		typ uint32
+		len int32
+		val string
+	}
+	counts := make(map[key]int)
+	for e := tl.objects.Front(); e != nil; e = e.Next() {
+		tlv := e.Value.(TLV)
+		counts[key{TypeU32(tlv), tlv.Length(), string(tlv.Value())}]++
+	}
+	for e := other.objects.Front(); e != nil; e = e.Next() {
+		tlv := e.Value.(TLV)
+		k := key{TypeU32(tlv), tlv.Length(), string(tlv.Value())}
+		if counts[k] == 0 {
+			return false
+		}
+		counts[k]--
+	}
+	return true
+}
+
 // Length returns the number of objects int the TLVList.
 func (tl *List) Length() int32 {
 	return int32(tl.objects.Len())
 }
 
+// IsEmpty reports whether the TLVList holds no objects.
+func (tl *List) IsEmpty() bool {
+	return tl.objects.Len() == 0
+}
+
+// Clear removes all objects from the TLVList, leaving it ready for reuse.
+func (tl *List) Clear() {
+	tl.objects.Init()
+}
+
+// Size returns the number of bytes Write writes for the TLVList.
+func (tl *List) Size() int {
+	var n int
+	for e := tl.objects.Front(); e != nil; e = e.Next() {
+		n += Size(e.Value.(TLV))
+	}
+	return n
+}
+
 // Get checks the TLVList for any object matching the type, It returns the first one found.
 // If the type could not be found, Get returns ErrTypeNotFound.
 func (tl *List) Get(typ byte) (TLV, error) {
 	for e := tl.objects.Front(); e != nil; e = e.Next() {
-		if e.Value.(*object).Type() == typ {
-			return e.Value.(*object), nil
+		if hasType(e.Value.(TLV), typ) {
+			return e.Value.(TLV), nil
 		}
 	}
 	return nil, ErrTypeNotFound
 }
 
+// GetValue returns the value of the first object matching the type.
+// The value is shared with the object. If the type could not be found, GetValue returns ErrTypeNotFound.
+func (tl *List) GetValue(typ byte) ([]byte, error) {
+	tlv, err := tl.Get(typ)
+	if err != nil {
+		return nil, err
+	}
+	return tlv.Value(), nil
+}
+
+// GetValueCopy is like GetValue, but returns a copy of the value.
+func (tl *List) GetValueCopy(typ byte) ([]byte, error) {
+	tlv, err := tl.Get(typ)
+	if err != nil {
+		return nil, err
+	}
+	return ValueCopy(tlv), nil
+}
+
+// GetLast checks the TLVList for any object matching the type, It returns the last one found.
+// If the type could not be found, GetLast returns ErrTypeNotFound.
+func (tl *List) GetLast(typ byte) (TLV, error) {
+	for e := tl.objects.Back(); e != nil; e = e.Prev() {
+		if hasType(e.Value.(TLV), typ) {
+			return e.Value.(TLV), nil
+		}
+	}
+	return nil, ErrTypeNotFound
+}
+
+// GetN returns the n-th object matching the type, counting from zero.
+// If there are not more than n objects of the type, GetN returns ErrTypeNotFound.
+func (tl *List) GetN(typ byte, n int) (TLV, error) {
+	for e := tl.objects.Front(); e != nil && n >= 0; e = e.Next() {
+		if hasType(e.Value.(TLV), typ) {
+			if n == 0 {
+				return e.Value.(TLV), nil
+			}
+			n--
+		}
+	}
+	return nil, ErrTypeNotFound
+}
+
+// At returns the object at the zero-based position i, whatever its type.
+// A negative i counts back from the end, so -1 is the last object.
+// If i is outside the TLVList, At returns ErrIndexOutOfRange.
+func (tl *List) At(i int) (TLV, error) {
+	if i >= tl.objects.Len() || i < -tl.objects.Len() {
+		return nil, ErrIndexOutOfRange
+	}
+
+	if i < 0 {
+		e := tl.objects.Back()
+		for ; i < -1; i++ {
+			e = e.Prev()
+		}
+		return e.Value.(TLV), nil
+	}
+	e := tl.objects.Front()
+	for ; i > 0; i-- {
+		e = e.Next()
+	}
+	return e.Value.(TLV), nil
+}
+
 // GetAll checks the TLVList for all objects matching the type, returning a slice containing all matching objects.
 // If no object has the requested type, an empty slice is returned.
 func (tl *List) GetAll(typ byte) []TLV {
 	ts := make([]TLV, 0)
 	for e := tl.objects.Front(); e != nil; e = e.Next() {
-		if e.Value.(*object).Type() == typ {
+		if hasType(e.Value.(TLV), typ) {
+			ts = append(ts, e.Value.(TLV))
+		}
+	}
+	return ts
+}
+
+// Count returns the number of objects matching the type.
+func (tl *List) Count(typ byte) int {
+	var n int
+	for e := tl.objects.Front(); e != nil; e = e.Next() {
+		if hasType(e.Value.(TLV), typ) {
+			n++
+		}
+	}
+	return n
+}
+
+// Histogram returns the number of objects of each type in the TLVList.
+// Objects of types wider than one byte are not counted.
+func (tl *List) Histogram() map[byte]int {
+	h := make(map[byte]int)
+	for e := tl.objects.Front(); e != nil; e = e.Next() {
+		if typ, ok := narrowType(e.Value.(TLV)); ok {
+			h[typ]++
+		}
+	}
+	return h
+}
+
+// TypeSet returns the distinct types of the objects in the TLVList.
+// Types wider than one byte are left out.
+func (tl *List) TypeSet() map[byte]struct{} {
+	set := make(map[byte]struct{})
+	for e := tl.objects.Front(); e != nil; e = e.Next() {
+		if typ, ok := narrowType(e.Value.(TLV)); ok {
+			set[typ] = struct{}{}
+		}
+	}
+	return set
+}
+
+// Contains returns true if the TLVList holds an object Equal to obj.
+func (tl *List) Contains(obj TLV) bool {
+	for e := tl.objects.Front(); e != nil; e = e.Next() {
+		if Equal(e.Value.(TLV), obj) {
+			return true
+		}
+	}
+	return false
+}
+
+// FindByValue returns the first object matching both the type and the value.
+// If no object matches, FindByValue returns ErrTypeNotFound.
+func (tl *List) FindByValue(typ byte, val []byte) (TLV, error) {
+	for e := tl.objects.Front(); e != nil; e = e.Next() {
+		tlv := e.Value.(TLV)
+		if hasType(tlv, typ) && bytes.Equal(tlv.Value(), val) {
+			return tlv, nil
+		}
+	}
+	return nil, ErrTypeNotFound
+}
+
+// FindByValuePrefix returns all objects matching the type whose value starts with prefix, in order.
+// If no object matches, an empty slice is returned.
+func (tl *List) FindByValuePrefix(typ byte, prefix []byte) []TLV {
+	return tl.FindAll(func(tlv TLV) bool {
+		return hasType(tlv, typ) && bytes.HasPrefix(tlv.Value(), prefix)
+	})
+}
+
+// FindAll returns all objects for which pred returns true, in order.
+// If no object matches, an empty slice is returned.
+func (tl *List) FindAll(pred func(TLV) bool) []TLV {
+	ts := make([]TLV, 0)
+	for e := tl.objects.Front(); e != nil; e = e.Next() {
+		if pred(e.Value.(TLV)) {
 			ts = append(ts, e.Value.(TLV))
 		}
 	}
 	return ts
 }
 
+// Each calls fn for each object in the TLVList, in insertion order.
+// Iteration stops early if fn returns false.
+func (tl *List) Each(fn func(TLV) bool) {
+	for e := tl.objects.Front(); e != nil; e = e.Next() {
+		if !fn(e.Value.(TLV)) {
+			return
+		}
+	}
+}
+
+// Filter returns a new TLVList containing the objects for which pred returns true, in order.
+func (tl *List) Filter(pred func(TLV) bool) *List {
+	filtered := NewList()
+	for e := tl.objects.Front(); e != nil; e = e.Next() {
+		if pred(e.Value.(TLV)) {
+			filtered.objects.PushBack(e.Value)
+		}
+	}
+	return filtered
+}
+
+// Map returns a new TLVList containing the result of calling fn on each object, in order.
+func (tl *List) Map(fn func(TLV) TLV) *List {
+	mapped := NewList()
+	for e := tl.objects.Front(); e != nil; e = e.Next() {
+		mapped.objects.PushBack(fn(e.Value.(TLV)))
+	}
+	return mapped
+}
+
 // Remove removes all objects with the requested type.
 // It returns a count of the number of removed objects.
 func (tl *List) Remove(typ byte) int {
@@ -189,7 +632,7 @@ func (tl *List) Remove(typ byte) int {
 	for {
 		var removed int
 		for e := tl.objects.Front(); e != nil; e = e.Next() {
-			if e.Value.(*object).Type() == typ {
+			if hasType(e.Value.(TLV), typ) {
 				tl.objects.Remove(e)
 				removed++
 				break
@@ -203,6 +646,50 @@ func (tl *List) Remove(typ byte) int {
 	return totalRemoved
 }
 
+// RemoveTypes removes all objects with any of the requested types in a single pass.
+// It returns a count of the number of removed objects.
+func (tl *List) RemoveTypes(types ...byte) int {
+	var set [256]bool
+	for _, typ := range types {
+		set[typ] = true
+	}
+
+	var removed int
+	for e := tl.objects.Front(); e != nil; {
+		next := e.Next()
+		if typ, ok := narrowType(e.Value.(TLV)); ok && set[typ] {
+			tl.objects.Remove(e)
+			removed++
+		}
+		e = next
+	}
+	return removed
+}
+
+// RemoveFirst removes the first object with the requested type.
+// It returns true if an object was removed.
+func (tl *List) RemoveFirst(typ byte) bool {
+	for e := tl.objects.Front(); e != nil; e = e.Next() {
+		if hasType(e.Value.(TLV), typ) {
+			tl.objects.Remove(e)
+			return true
+		}
+	}
+	return false
+}
+
+// RemoveLast removes the last object with the requested type.
+// It returns true if an object was removed.
+func (tl *List) RemoveLast(typ byte) bool {
+	for e := tl.objects.Back(); e != nil; e = e.Prev() {
+		if hasType(e.Value.(TLV), typ) {
+			tl.objects.Remove(e)
+			return true
+		}
+	}
+	return false
+}
+
 // RemoveObject takes an TLV object as an argument, and removes all matching objects.
 // It matches on not just type, but also the value contained in the object.
 func (tl *List) RemoveObject(obj TLV) int {
@@ -210,7 +697,7 @@ func (tl *List) RemoveObject(obj TLV) int {
 	for {
 		var removed int
 		for e := tl.objects.Front(); e != nil; e = e.Next() {
-			if Equal(e.Value.(*object), obj) {
+			if Equal(e.Value.(TLV), obj) {
 				tl.objects.Remove(e)
 				removed++
 				break
@@ -231,36 +718,280 @@ func (tl *List) Add(typ byte, value []byte) {
 	tl.objects.PushBack(obj)
 }
 
+// AddUnique pushes a new TLV object onto the TLVList unless an object of the same type exists.
+// In that case it returns an error wrapping ErrDuplicateType and the TLVList is unchanged.
+func (tl *List) AddUnique(typ byte, value []byte) error {
+	if _, err := tl.Get(typ); err == nil {
+		return fmt.Errorf("%w: 0x%02x", ErrDuplicateType, typ)
+	}
+	tl.Add(typ, value)
+	return nil
+}
+
 // AddObject adds a TLV object onto the TLVList
 func (tl *List) AddObject(obj TLV) {
 	tl.objects.PushBack(obj)
 }
 
+// SetValidator sets the function AddValidated and AddObjectValidated check objects with.
+// A nil validator accepts everything. Add and AddObject don't consult it.
+func (tl *List) SetValidator(validate func(typ byte, val []byte) error) {
+	tl.validate = validate
+}
+
+// AddValidated pushes a new TLV object onto the TLVList if the validator accepts it.
+// Otherwise it returns the validator's error and the TLVList is unchanged.
+func (tl *List) AddValidated(typ byte, value []byte) error {
+	return tl.AddObjectValidated(New(typ, value))
+}
+
+// AddObjectValidated adds a TLV object onto the TLVList if the validator accepts it.
+// Otherwise it returns the validator's error and the TLVList is unchanged.
+func (tl *List) AddObjectValidated(obj TLV) error {
+	if tl.validate != nil {
+		if err := tl.validate(obj.Type(), obj.Value()); err != nil {
+			return err
+		}
+	}
+	tl.objects.PushBack(obj)
+	return nil
+}
+
+// Append pushes all of other's objects onto the TLVList, in order.
+// The objects are shared between both TLVLists.
+func (tl *List) Append(other *List) {
+	tl.objects.PushBackList(other.objects)
+}
+
+// AppendClone pushes a copy of each of other's objects onto the TLVList, in order.
+func (tl *List) AppendClone(other *List) {
+	tl.objects.PushBackList(other.Clone().objects)
+}
+
+// InsertBefore inserts a TLV object before the first object matching the type.
+// If the type could not be found, InsertBefore returns ErrTypeNotFound and the TLVList is unchanged.
+func (tl *List) InsertBefore(typ byte, obj TLV) error {
+	for e := tl.objects.Front(); e != nil; e = e.Next() {
+		if hasType(e.Value.(TLV), typ) {
+			tl.objects.InsertBefore(obj, e)
+			return nil
+		}
+	}
+	return ErrTypeNotFound
+}
+
+// Replace swaps the first object matching the type for a TLV object, keeping its position.
+// It returns true if an object was replaced.
+func (tl *List) Replace(typ byte, obj TLV) bool {
+	for e := tl.objects.Front(); e != nil; e = e.Next() {
+		if hasType(e.Value.(TLV), typ) {
+			e.Value = obj
+			return true
+		}
+	}
+	return false
+}
+
+// Set replaces the value of the first object matching the type, keeping its position,
+// or pushes a new object if none matches. It returns true if an object was replaced.
+func (tl *List) Set(typ byte, value []byte) bool {
+	if tl.Replace(typ, New(typ, value)) {
+		return true
+	}
+	tl.Add(typ, value)
+	return false
+}
+
+// Merge collapses all objects matching the type into one, at the position of the first,
+// whose value is built by folding reduce over their values in order.
+// It returns true if two or more objects were merged.
+func (tl *List) Merge(typ byte, reduce func(a, b []byte) []byte) bool {
+	var first *list.Element
+	var val []byte
+	var merged bool
+	for e := tl.objects.Front(); e != nil; {
+		next := e.Next()
+		if !hasType(e.Value.(TLV), typ) {
+			e = next
+			continue
+		}
+		if first == nil {
+			first = e
+			val = e.Value.(TLV).Value()
+		} else {
+			val = reduce(val, e.Value.(TLV).Value())
+			tl.objects.Remove(e)
+			merged = true
+		}
+		e = next
+	}
+	if merged {
+		first.Value = NewTypeU32(TypeU32(first.Value.(TLV)), val)
+	}
+	return merged
+}
+
+// CompareType orders TLV objects by their full type, compared as an unsigned number,
+// so 0xff sorts after 0x01. It returns -1, 0 or +1 as a's type is less than, equal to,
+// or greater than b's.
+func CompareType(a, b TLV) int {
+	ta, tb := TypeU32(a), TypeU32(b)
+	if ta < tb {
+		return -1
+	} else if ta > tb {
+		return 1
+	}
+	return 0
+}
+
+// SortByType sorts the TLVList by ascending type in place, ordered by CompareType.
+// The sort is stable, so objects of the same type keep their relative order.
+func (tl *List) SortByType() {
+	elems := make([]*list.Element, 0, tl.objects.Len())
+	for e := tl.objects.Front(); e != nil; e = e.Next() {
+		elems = append(elems, e)
+	}
+	sort.SliceStable(elems, func(i, j int) bool {
+		return CompareType(elems[i].Value.(TLV), elems[j].Value.(TLV)) < 0
+	})
+	for _, e := range elems {
+		tl.objects.MoveToBack(e)
+	}
+}
+
+// Reverse reverses the order of the TLVList in place.
+func (tl *List) Reverse() {
+	for e := tl.objects.Front(); e != nil; {
+		next := e.Next()
+		tl.objects.MoveToFront(e)
+		e = next
+	}
+}
+
 // Write writes out the TLVList to an io.Writer.
 func (tl *List) Write(w io.Writer) error {
+	return defaultCodec.Write(tl, w)
+}
+
+// AppendTo appends the bytes Write would write for the TLVList to dst, and returns the
+// extended slice. It fails on the first object AppendEncoded refuses, in which case the
+// slice holds the objects before it, as Write would have written them.
+func (tl *List) AppendTo(dst []byte) ([]byte, error) {
+	dst = slices.Grow(dst, tl.Size())
 	for e := tl.objects.Front(); e != nil; e = e.Next() {
-		err := WriteObject(e.Value.(TLV), w)
-		if err != nil {
-			return err
+		var err error
+		if dst, err = AppendEncoded(dst, e.Value.(TLV)); err != nil {
+			return dst, err
+		}
+	}
+	return dst, nil
+}
+
+// WriteOrdered writes out the TLVList to an io.Writer with the types listed in order first,
+// in that order, followed by the objects of all other types. Objects of the same type keep
+// their insertion order. The TLVList itself is unchanged.
+func (tl *List) WriteOrdered(w io.Writer, order []byte) error {
+	var listed [256]bool
+	for _, typ := range order {
+		if listed[typ] {
+			continue
+		}
+		listed[typ] = true
+		for e := tl.objects.Front(); e != nil; e = e.Next() {
+			if hasType(e.Value.(TLV), typ) {
+				if err := WriteObject(e.Value.(TLV), w); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for e := tl.objects.Front(); e != nil; e = e.Next() {
+		if typ, ok := narrowType(e.Value.(TLV)); !ok || !listed[typ] {
+			if err := WriteObject(e.Value.(TLV), w); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
 }
 
 // Read takes an io.Reader and builds a TLVList from that.
+// On a malformed object the partially built list is returned along with the error.
 func Read(r io.Reader) (*List, error) {
+	return defaultCodec.Read(r)
+}
+
+// ReadAll builds a TLVList from io.Reader like Read, and also returns the number of bytes
+// taken by the objects read. On error that count excludes the failing object, so it is the
+// offset at which data following the last complete object starts.
+func ReadAll(r io.Reader) (*List, int64, error) {
 	tl := NewList()
-	var err error
-	for {
-		var tlv TLV
-		if tlv, err = ReadObject(r); err != nil {
-			break
-		}
-		tl.objects.PushBack(tlv)
-	}
+	n, err := defaultCodec.readInto(tl, r, readHooks{})
+	return tl, n, err
+}
 
-	if err == io.EOF {
-		err = nil
-	}
+// ReadUntil builds a TLVList from io.Reader like Read, but stops after an object of stopType,
+// which is consumed but not added to the list. Nothing following it is read.
+// If no such object appears, the whole stream is read.
+func ReadUntil(r io.Reader, stopType byte) (*List, error) {
+	tl := NewList()
+	_, err := defaultCodec.readInto(tl, r, readHooks{
+		after: func(_ int, tlv TLV) (bool, bool, error) {
+			stop := hasType(tlv, stopType)
+			return !stop, stop, nil
+		},
+	})
 	return tl, err
 }
+
+// ReadVersioned reads a one-byte format version from io.Reader, then builds a TLVList
+// from the rest of the stream like Read. It returns io.EOF if the stream is empty.
+func ReadVersioned(r io.Reader) (byte, *List, error) {
+	var version [1]byte
+	if _, err := io.ReadFull(r, version[:]); err != nil {
+		return 0, nil, err
+	}
+	tl, err := Read(r)
+	return version[0], tl, err
+}
+
+// WriteTo writes out the TLVList to an io.Writer, returning the number of bytes written.
+// It implements io.WriterTo.
+func (tl *List) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	err := tl.Write(cw)
+	return cw.n, err
+}
+
+// ReadFrom reads objects from an io.Reader until EOF and appends them to the TLVList,
+// returning the number of bytes consumed. It implements io.ReaderFrom.
+func (tl *List) ReadFrom(r io.Reader) (int64, error) {
+	cr := &countingReader{r: r}
+	_, err := defaultCodec.readInto(tl, cr, readHooks{})
+	return cr.n, err
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
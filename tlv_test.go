@@ -1,10 +1,17 @@
 package tlv
 
 import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
+	"strings"
 	"testing"
+	"testing/iotest"
 )
 
 const (
@@ -65,6 +72,214 @@ func TestTLVRead(t *testing.T) {
 
 }
 
+func TestTLVReadShort(t *testing.T) {
+	tlv := New(TypeTest3, []byte("gophers are everywhere!"))
+	data, err := ToBytes(tlv)
+	if err != nil {
+		FailWithError(t, "TestTLVReadShort", err)
+	}
+
+	tmpTLV, err := ReadObject(iotest.OneByteReader(bytes.NewReader(data)))
+	if err != nil {
+		FailWithError(t, "TestTLVReadShort", err)
+	} else if !Equal(tlv, tmpTLV) {
+		FailWithError(t, "TestTLVReadShort", errNoMatch)
+	}
+
+	for _, n := range []int{len(data) - 1, 5} {
+		_, err = ReadObject(bytes.NewReader(data[:n]))
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			FailWithError(t, "TestTLVReadShort",
+				fmt.Errorf("expected io.ErrUnexpectedEOF, got %v", err))
+		}
+	}
+}
+
+func TestTLVNewChecked(t *testing.T) {
+	allowed := map[byte]bool{TypeTest1: true, TypeTest2: true}
+
+	tlv, err := NewChecked(TypeTest2, []byte("foo bar"), allowed)
+	if err != nil {
+		FailWithError(t, "TestTLVNewChecked", err)
+	} else if !Equal(tlv, New(TypeTest2, []byte("foo bar"))) {
+		FailWithError(t, "TestTLVNewChecked", errNoMatch)
+	}
+
+	tlv, err = NewChecked(TypeTest6, []byte("foo bar"), allowed)
+	if !errors.Is(err, ErrTypeNotAllowed) || tlv != nil {
+		FailWithError(t, "TestTLVNewChecked",
+			fmt.Errorf("expected ErrTypeNotAllowed, got %v", err))
+	} else if !strings.Contains(err.Error(), "0x05") {
+		FailWithError(t, "TestTLVNewChecked",
+			fmt.Errorf("error %q does not name the type", err))
+	}
+}
+
+func TestTLVBinaryMarshaler(t *testing.T) {
+	tlv := New(TypeTest3, []byte("gophers are everywhere!"))
+	if _, ok := tlv.(encoding.BinaryMarshaler); !ok {
+		FailWithError(t, "TestTLVBinaryMarshaler",
+			fmt.Errorf("object is not an encoding.BinaryMarshaler"))
+	}
+
+	buf := new(bytes.Buffer)
+	if err := gob.NewEncoder(buf).Encode(tlv); err != nil {
+		FailWithError(t, "TestTLVBinaryMarshaler", err)
+	}
+	tmpTLV := new(object)
+	if err := gob.NewDecoder(buf).Decode(tmpTLV); err != nil {
+		FailWithError(t, "TestTLVBinaryMarshaler", err)
+	} else if !Equal(tlv, tmpTLV) {
+		FailWithError(t, "TestTLVBinaryMarshaler", errNoMatch)
+	}
+
+	if err := tmpTLV.UnmarshalBinary([]byte{TypeTest1, 0}); err == nil {
+		FailWithError(t, "TestTLVBinaryMarshaler",
+			fmt.Errorf("truncated data should fail to unmarshal"))
+	}
+}
+
+func TestTLVClone(t *testing.T) {
+	tlv := New(TypeTest1, []byte("foo bar"))
+	clone := Clone(tlv)
+	if !Equal(tlv, clone) {
+		FailWithError(t, "TestTLVClone", errNoMatch)
+	}
+
+	copy(clone.Value(), "XXX")
+	if !Equal(tlv, New(TypeTest1, []byte("foo bar"))) {
+		FailWithError(t, "TestTLVClone",
+			fmt.Errorf("source modified through clone"))
+	} else if Equal(tlv, clone) {
+		FailWithError(t, "TestTLVClone",
+			fmt.Errorf("clone shares value with source"))
+	}
+
+	if Clone(nil) != nil {
+		FailWithError(t, "TestTLVClone",
+			fmt.Errorf("clone of nil should be nil"))
+	}
+}
+
+func TestTLVValueCopy(t *testing.T) {
+	tlv := New(TypeTest1, []byte("foo bar"))
+	val := ValueCopy(tlv)
+	if string(val) != "foo bar" {
+		FailWithError(t, "TestTLVValueCopy", errNoMatch)
+	}
+
+	copy(val, "XXX")
+	if !Equal(tlv, New(TypeTest1, []byte("foo bar"))) {
+		FailWithError(t, "TestTLVValueCopy",
+			fmt.Errorf("source modified through value copy"))
+	}
+}
+
+func TestTLVReadTruncatedHeader(t *testing.T) {
+	if _, err := ReadObject(bytes.NewReader(nil)); err != io.EOF {
+		FailWithError(t, "TestTLVReadTruncatedHeader",
+			fmt.Errorf("empty stream: expected io.EOF, got %v", err))
+	}
+	for _, raw := range [][]byte{{TypeTest1}, {TypeTest1, 0, 0}} {
+		_, err := ReadObject(bytes.NewReader(raw))
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			FailWithError(t, "TestTLVReadTruncatedHeader",
+				fmt.Errorf("%d bytes: expected io.ErrUnexpectedEOF, got %v", len(raw), err))
+		}
+
+		tlvl, err := Read(bytes.NewReader(raw))
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			FailWithError(t, "TestTLVReadTruncatedHeader",
+				fmt.Errorf("%d bytes: Read should fail, got %v", len(raw), err))
+		} else if tlvl.Length() != 0 {
+			FailWithError(t, "TestTLVReadTruncatedHeader",
+				fmt.Errorf("%d bytes: %d records read", len(raw), tlvl.Length()))
+		}
+	}
+}
+
+func TestTLVZeroLength(t *testing.T) {
+	for _, val := range [][]byte{nil, {}} {
+		tlv := New(TypeTest1, val)
+		if tlv.Length() != 0 {
+			FailWithError(t, "TestTLVZeroLength",
+				fmt.Errorf("length %d, expected 0", tlv.Length()))
+		} else if tlv.Value() == nil || len(tlv.Value()) != 0 {
+			FailWithError(t, "TestTLVZeroLength",
+				fmt.Errorf("value should be empty and non-nil"))
+		}
+
+		data, err := ToBytes(tlv)
+		if err != nil {
+			FailWithError(t, "TestTLVZeroLength", err)
+		} else if len(data) != 5 {
+			FailWithError(t, "TestTLVZeroLength",
+				fmt.Errorf("encoded %d bytes, expected 5", len(data)))
+		}
+
+		// Any read past the header would hit the failing reader.
+		r := io.MultiReader(bytes.NewReader(data), iotest.ErrReader(io.ErrClosedPipe))
+		tmpTLV, err := ReadObject(r)
+		if err != nil {
+			FailWithError(t, "TestTLVZeroLength", err)
+		} else if tmpTLV.Length() != 0 || tmpTLV.Value() == nil || len(tmpTLV.Value()) != 0 {
+			FailWithError(t, "TestTLVZeroLength",
+				fmt.Errorf("decoded a non-empty object"))
+		} else if !Equal(tlv, tmpTLV) {
+			FailWithError(t, "TestTLVZeroLength", errNoMatch)
+		}
+	}
+}
+
+func TestTLVAppendEncoded(t *testing.T) {
+	tlv := New(TypeTest3, []byte("gophers are everywhere!"))
+	expected, err := ToBytes(tlv)
+	if err != nil {
+		FailWithError(t, "TestTLVAppendEncoded", err)
+	}
+	if data, err := AppendEncoded(nil, tlv); err != nil {
+		FailWithError(t, "TestTLVAppendEncoded", err)
+	} else if !bytes.Equal(data, expected) {
+		FailWithError(t, "TestTLVAppendEncoded",
+			fmt.Errorf("AppendEncoded differs from ToBytes"))
+	}
+
+	prefix := []byte("prefix")
+	data, err := AppendEncoded(prefix, tlv)
+	if err != nil {
+		FailWithError(t, "TestTLVAppendEncoded", err)
+	} else if !bytes.HasPrefix(data, prefix) || !bytes.Equal(data[len(prefix):], expected) {
+		FailWithError(t, "TestTLVAppendEncoded",
+			fmt.Errorf("AppendEncoded did not append to dst"))
+	}
+
+	wide := NewTypeU32(0x1234, []byte("foo bar"))
+	if _, err := ToBytes(wide); err != ErrTypeOverflow {
+		FailWithError(t, "TestTLVAppendEncoded",
+			fmt.Errorf("ToBytes: expected ErrTypeOverflow, got %v", err))
+	}
+	if data, err := AppendEncoded(prefix, wide); err != ErrTypeOverflow {
+		FailWithError(t, "TestTLVAppendEncoded",
+			fmt.Errorf("expected ErrTypeOverflow, got %v", err))
+	} else if !bytes.Equal(data, prefix) {
+		FailWithError(t, "TestTLVAppendEncoded",
+			fmt.Errorf("refused object appended % x", data[len(prefix):]))
+	}
+}
+
+func TestTLVWriteObjectN(t *testing.T) {
+	for _, val := range [][]byte{nil, []byte("gophers are everywhere!")} {
+		buf := new(bytes.Buffer)
+		n, err := WriteObjectN(New(TypeTest1, val), buf)
+		if err != nil {
+			FailWithError(t, "TestTLVWriteObjectN", err)
+		} else if n != 5+len(val) || n != buf.Len() {
+			FailWithError(t, "TestTLVWriteObjectN",
+				fmt.Errorf("wrote %d bytes, expected %d", n, 5+len(val)))
+		}
+	}
+}
+
 func TestTLVListAdd(t *testing.T) {
 	tlvl := NewList()
 
@@ -167,6 +382,71 @@ func TestTLVListRemoveRecords(t *testing.T) {
 	}
 }
 
+func TestTLVListClone(t *testing.T) {
+	tlvl := NewList()
+	tlvl.Add(TypeTest1, []byte("foo bar"))
+	tlvl.Add(TypeTest2, []byte("baz quux"))
+
+	clone := tlvl.Clone()
+	if clone.Length() != tlvl.Length() {
+		FailWithError(t, "TestTLVListClone",
+			fmt.Errorf("clone has %d records, expected %d",
+				clone.Length(), tlvl.Length()))
+	}
+
+	clone.Each(func(tlv TLV) bool {
+		copy(tlv.Value(), "XXX")
+		return true
+	})
+	clone.Add(TypeTest3, []byte("gophers are everywhere!"))
+
+	if tlvl.Length() != 2 {
+		FailWithError(t, "TestTLVListClone",
+			fmt.Errorf("source length changed through clone"))
+	}
+	tmpTLV, err := tlvl.Get(TypeTest1)
+	if err != nil {
+		FailWithError(t, "TestTLVListClone", err)
+	} else if !Equal(tmpTLV, New(TypeTest1, []byte("foo bar"))) {
+		FailWithError(t, "TestTLVListClone",
+			fmt.Errorf("source modified through clone"))
+	}
+}
+
+func TestTLVListEach(t *testing.T) {
+	tlvl := NewList()
+	tlvl.Add(TypeTest1, []byte("foo bar"))
+	tlvl.Add(TypeTest2, []byte("baz quux"))
+	tlvl.Add(TypeTest2, []byte("goodbye, cruel world"))
+
+	var total int32
+	tlvl.Each(func(tlv TLV) bool {
+		total += tlv.Length()
+		return true
+	})
+	if expected := int32(len("foo bar") + len("baz quux") + len("goodbye, cruel world")); total != expected {
+		FailWithError(t, "TestTLVListEach",
+			fmt.Errorf("summed length %d, expected %d", total, expected))
+	}
+
+	var visited int
+	var found TLV
+	tlvl.Each(func(tlv TLV) bool {
+		visited++
+		if tlv.Type() == TypeTest2 {
+			found = tlv
+			return false
+		}
+		return true
+	})
+	if visited != 2 {
+		FailWithError(t, "TestTLVListEach",
+			fmt.Errorf("visited %d records, expected 2", visited))
+	} else if !Equal(found, New(TypeTest2, []byte("baz quux"))) {
+		FailWithError(t, "TestTLVListEach", errNoMatch)
+	}
+}
+
 func TestTLVListReadWrite(t *testing.T) {
 	tlvl := NewList()
 
@@ -242,3 +522,1378 @@ func TestTLVListReadWrite(t *testing.T) {
 		}
 	}
 }
+
+func TestTLVListReadTruncated(t *testing.T) {
+	tlvl := NewList()
+	tlvl.Add(TypeTest1, []byte("foo bar"))
+	tlvl.Add(TypeTest2, []byte("baz quux"))
+	tlvl.Add(TypeTest3, []byte("gophers are everywhere!"))
+
+	buf := new(bytes.Buffer)
+	if err := tlvl.Write(buf); err != nil {
+		FailWithError(t, "TestTLVListReadTruncated", err)
+	}
+	data := buf.Bytes()[:buf.Len()-3]
+
+	rtlvl, err := Read(bytes.NewReader(data))
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		FailWithError(t, "TestTLVListReadTruncated",
+			fmt.Errorf("expected io.ErrUnexpectedEOF, got %v", err))
+	} else if !strings.Contains(err.Error(), "object 2") {
+		FailWithError(t, "TestTLVListReadTruncated",
+			fmt.Errorf("error %q does not name object 2", err))
+	}
+	if rtlvl.Length() != 2 {
+		FailWithError(t, "TestTLVListReadTruncated",
+			fmt.Errorf("%d records read, expected 2", rtlvl.Length()))
+	}
+	if _, err := rtlvl.Get(TypeTest2); err != nil {
+		FailWithError(t, "TestTLVListReadTruncated", err)
+	}
+}
+
+func TestTLVListWriteToReadFrom(t *testing.T) {
+	tlvs := []TLV{
+		New(TypeTest1, []byte("foo bar")),
+		New(TypeTest2, []byte("baz quux")),
+		New(TypeTest3, nil),
+	}
+	tlvl := NewList()
+	var expected int64
+	for _, tlv := range tlvs {
+		tlvl.AddObject(tlv)
+		data, err := ToBytes(tlv)
+		if err != nil {
+			FailWithError(t, "TestTLVListWriteToReadFrom", err)
+		}
+		expected += int64(len(data))
+	}
+
+	buf := new(bytes.Buffer)
+	n, err := tlvl.WriteTo(buf)
+	if err != nil {
+		FailWithError(t, "TestTLVListWriteToReadFrom", err)
+	} else if n != expected || int64(buf.Len()) != expected {
+		FailWithError(t, "TestTLVListWriteToReadFrom",
+			fmt.Errorf("wrote %d bytes, expected %d", n, expected))
+	}
+
+	rtlvl := NewList()
+	rtlvl.Add(TypeTest4, []byte("already here"))
+	n, err = rtlvl.ReadFrom(buf)
+	if err != nil {
+		FailWithError(t, "TestTLVListWriteToReadFrom", err)
+	} else if n != expected {
+		FailWithError(t, "TestTLVListWriteToReadFrom",
+			fmt.Errorf("read %d bytes, expected %d", n, expected))
+	}
+	if rtlvl.Length() != 4 {
+		FailWithError(t, "TestTLVListWriteToReadFrom",
+			fmt.Errorf("%d records, expected 4", rtlvl.Length()))
+	}
+}
+
+func TestTLVListInsertBefore(t *testing.T) {
+	tlvl := NewList()
+	tlvl.Add(TypeTest2, []byte("baz quux"))
+	tlvl.Add(TypeTest3, []byte("gophers are everywhere!"))
+
+	if err := tlvl.InsertBefore(TypeTest2, New(TypeTest1, []byte("foo bar"))); err != nil {
+		FailWithError(t, "TestTLVListInsertBefore", err)
+	}
+	if err := tlvl.InsertBefore(TypeTest3, New(TypeTest4, []byte("second"))); err != nil {
+		FailWithError(t, "TestTLVListInsertBefore", err)
+	}
+	if err := tlvl.InsertBefore(TypeTest6, New(TypeTest5, nil)); err != ErrTypeNotFound {
+		FailWithError(t, "TestTLVListInsertBefore",
+			fmt.Errorf("expected ErrTypeNotFound, got %v", err))
+	}
+
+	expected := []byte{TypeTest1, TypeTest2, TypeTest4, TypeTest3}
+	var types []byte
+	tlvl.Each(func(tlv TLV) bool {
+		types = append(types, tlv.Type())
+		return true
+	})
+	if !bytes.Equal(types, expected) {
+		FailWithError(t, "TestTLVListInsertBefore",
+			fmt.Errorf("types %v, expected %v", types, expected))
+	}
+}
+
+func TestTLVListReplace(t *testing.T) {
+	tlvl := NewList()
+	tlvl.Add(TypeTest1, []byte("foo bar"))
+	tlvl.Add(TypeTest2, []byte("baz quux"))
+	tlvl.Add(TypeTest2, []byte("goodbye, cruel world"))
+
+	replacement := New(TypeTest2, []byte("replaced"))
+	if !tlvl.Replace(TypeTest2, replacement) {
+		FailWithError(t, "TestTLVListReplace",
+			fmt.Errorf("record not replaced"))
+	}
+	if tlvl.Replace(TypeTest3, New(TypeTest3, nil)) {
+		FailWithError(t, "TestTLVListReplace",
+			fmt.Errorf("missing type should not be replaced"))
+	}
+
+	tlvs := tlvl.GetAll(TypeTest2)
+	if tlvl.Length() != 3 || len(tlvs) != 2 {
+		FailWithError(t, "TestTLVListReplace",
+			fmt.Errorf("unexpected record count"))
+	} else if !Equal(tlvs[0], replacement) {
+		FailWithError(t, "TestTLVListReplace", errNoMatch)
+	} else if !Equal(tlvs[1], New(TypeTest2, []byte("goodbye, cruel world"))) {
+		FailWithError(t, "TestTLVListReplace", errNoMatch)
+	}
+}
+
+func TestTLVListFilter(t *testing.T) {
+	tlvl := NewList()
+	tlvl.Add(TypeTest1, []byte("foo bar"))
+	tlvl.Add(TypeTest4, []byte("baz quux"))
+	tlvl.Add(TypeTest2, []byte("goodbye, cruel world"))
+	tlvl.Add(TypeTest5, []byte("gophers are everywhere!"))
+
+	filtered := tlvl.Filter(func(tlv TLV) bool {
+		return tlv.Type() >= TypeTest2 && tlv.Type() <= TypeTest4
+	})
+	if filtered.Length() != 2 {
+		FailWithError(t, "TestTLVListFilter",
+			fmt.Errorf("%d records, expected 2", filtered.Length()))
+	}
+	var types []byte
+	filtered.Each(func(tlv TLV) bool {
+		types = append(types, tlv.Type())
+		return true
+	})
+	if !bytes.Equal(types, []byte{TypeTest4, TypeTest2}) {
+		FailWithError(t, "TestTLVListFilter",
+			fmt.Errorf("types %v out of order", types))
+	}
+	if tlvl.Length() != 4 {
+		FailWithError(t, "TestTLVListFilter",
+			fmt.Errorf("source list modified"))
+	}
+}
+
+func TestTLVListMap(t *testing.T) {
+	tlvl := NewList()
+	tlvl.Add(TypeTest1, []byte("foo bar"))
+	tlvl.Add(TypeTest2, []byte("baz quux"))
+
+	mapped := tlvl.Map(func(tlv TLV) TLV {
+		return New(tlv.Type(), bytes.ToUpper(tlv.Value()))
+	})
+	if tmpTLV, err := mapped.Get(TypeTest1); err != nil {
+		FailWithError(t, "TestTLVListMap", err)
+	} else if !Equal(tmpTLV, New(TypeTest1, []byte("FOO BAR"))) {
+		FailWithError(t, "TestTLVListMap", errNoMatch)
+	}
+	if tmpTLV, err := mapped.Get(TypeTest2); err != nil {
+		FailWithError(t, "TestTLVListMap", err)
+	} else if !Equal(tmpTLV, New(TypeTest2, []byte("BAZ QUUX"))) {
+		FailWithError(t, "TestTLVListMap", errNoMatch)
+	}
+
+	if tmpTLV, err := tlvl.Get(TypeTest1); err != nil {
+		FailWithError(t, "TestTLVListMap", err)
+	} else if !Equal(tmpTLV, New(TypeTest1, []byte("foo bar"))) {
+		FailWithError(t, "TestTLVListMap",
+			fmt.Errorf("source list modified"))
+	}
+}
+
+func TestTLVListHistogram(t *testing.T) {
+	tlvl := NewList()
+	if h := tlvl.Histogram(); len(h) != 0 {
+		FailWithError(t, "TestTLVListHistogram",
+			fmt.Errorf("empty list histogram has %d entries", len(h)))
+	}
+
+	tlvl.Add(TypeTest1, []byte("foo bar"))
+	tlvl.Add(TypeTest2, []byte("baz quux"))
+	tlvl.Add(TypeTest1, []byte("goodbye, cruel world"))
+	tlvl.Add(TypeTest1, nil)
+
+	h := tlvl.Histogram()
+	if len(h) != 2 || h[TypeTest1] != 3 || h[TypeTest2] != 1 {
+		FailWithError(t, "TestTLVListHistogram",
+			fmt.Errorf("unexpected histogram %v", h))
+	}
+	for _, typ := range []byte{TypeTest1, TypeTest2, TypeTest3} {
+		if n := tlvl.Count(typ); n != len(tlvl.GetAll(typ)) {
+			FailWithError(t, "TestTLVListHistogram",
+				fmt.Errorf("type %d: count %d, GetAll %d", typ, n, len(tlvl.GetAll(typ))))
+		}
+	}
+}
+
+func TestTLVListGetLast(t *testing.T) {
+	tlv1 := New(TypeTest1, []byte("foo bar"))
+	tlv2 := New(TypeTest1, []byte("baz quux"))
+	tlv3 := New(TypeTest1, []byte("goodbye, cruel world"))
+	tlvl := NewList()
+	tlvl.AddObject(tlv1)
+	tlvl.Add(TypeTest2, []byte("gophers are everywhere!"))
+	tlvl.AddObject(tlv2)
+	tlvl.AddObject(tlv3)
+
+	if tmpTLV, err := tlvl.Get(TypeTest1); err != nil {
+		FailWithError(t, "TestTLVListGetLast", err)
+	} else if !Equal(tmpTLV, tlv1) {
+		FailWithError(t, "TestTLVListGetLast", errNoMatch)
+	}
+	if tmpTLV, err := tlvl.GetLast(TypeTest1); err != nil {
+		FailWithError(t, "TestTLVListGetLast", err)
+	} else if !Equal(tmpTLV, tlv3) {
+		FailWithError(t, "TestTLVListGetLast", errNoMatch)
+	}
+	for n, expected := range []TLV{tlv1, tlv2, tlv3} {
+		if tmpTLV, err := tlvl.GetN(TypeTest1, n); err != nil {
+			FailWithError(t, "TestTLVListGetLast", err)
+		} else if !Equal(tmpTLV, expected) {
+			FailWithError(t, "TestTLVListGetLast", errNoMatch)
+		}
+	}
+
+	if _, err := tlvl.GetLast(TypeTest3); err != ErrTypeNotFound {
+		FailWithError(t, "TestTLVListGetLast",
+			fmt.Errorf("expected ErrTypeNotFound, got %v", err))
+	}
+	for _, n := range []int{-1, 3} {
+		if _, err := tlvl.GetN(TypeTest1, n); err != ErrTypeNotFound {
+			FailWithError(t, "TestTLVListGetLast",
+				fmt.Errorf("n=%d: expected ErrTypeNotFound, got %v", n, err))
+		}
+	}
+}
+
+func TestTLVListEqualUnordered(t *testing.T) {
+	tlvl1 := NewList()
+	tlvl1.Add(TypeTest1, []byte("foo bar"))
+	tlvl1.Add(TypeTest2, []byte("baz quux"))
+	tlvl1.Add(TypeTest1, []byte("goodbye, cruel world"))
+	tlvl1.Add(TypeTest1, []byte("foo bar"))
+
+	tlvl2 := NewList()
+	tlvl2.Add(TypeTest1, []byte("goodbye, cruel world"))
+	tlvl2.Add(TypeTest1, []byte("foo bar"))
+	tlvl2.Add(TypeTest1, []byte("foo bar"))
+	tlvl2.Add(TypeTest2, []byte("baz quux"))
+
+	if !tlvl1.EqualUnordered(tlvl2) || !tlvl2.EqualUnordered(tlvl1) {
+		FailWithError(t, "TestTLVListEqualUnordered",
+			fmt.Errorf("reordered lists should be EqualUnordered"))
+	}
+	if tlvl1.Equal(tlvl2) {
+		FailWithError(t, "TestTLVListEqualUnordered",
+			fmt.Errorf("reordered lists should not be Equal"))
+	}
+
+	tlvl3 := NewList()
+	tlvl3.Add(TypeTest1, []byte("goodbye, cruel world"))
+	tlvl3.Add(TypeTest1, []byte("goodbye, cruel world"))
+	tlvl3.Add(TypeTest1, []byte("foo bar"))
+	tlvl3.Add(TypeTest2, []byte("baz quux"))
+	if tlvl1.EqualUnordered(tlvl3) {
+		FailWithError(t, "TestTLVListEqualUnordered",
+			fmt.Errorf("lists with different repeat counts should differ"))
+	}
+
+	var nilList *List
+	if tlvl1.EqualUnordered(nilList) || nilList.EqualUnordered(tlvl1) {
+		FailWithError(t, "TestTLVListEqualUnordered",
+			fmt.Errorf("a nil list should not be EqualUnordered to a non-nil one"))
+	} else if !nilList.EqualUnordered(nil) {
+		FailWithError(t, "TestTLVListEqualUnordered",
+			fmt.Errorf("nil lists should be EqualUnordered"))
+	}
+}
+
+func TestTLVListEqual(t *testing.T) {
+	tlvl1 := NewList()
+	tlvl1.Add(TypeTest1, []byte("foo bar"))
+	tlvl1.Add(TypeTest2, []byte("baz quux"))
+
+	tlvl2 := NewList()
+	tlvl2.AddObject(New(TypeTest1, []byte("foo bar")))
+	tlvl2.AddObject(New(TypeTest2, []byte("baz quux")))
+	if !tlvl1.Equal(tlvl2) {
+		FailWithError(t, "TestTLVListEqual",
+			fmt.Errorf("identical lists should be Equal"))
+	}
+
+	reordered := NewList()
+	reordered.Add(TypeTest2, []byte("baz quux"))
+	reordered.Add(TypeTest1, []byte("foo bar"))
+	if tlvl1.Equal(reordered) {
+		FailWithError(t, "TestTLVListEqual",
+			fmt.Errorf("reordered lists should not be Equal"))
+	}
+
+	tlvl2.Add(TypeTest3, nil)
+	if tlvl1.Equal(tlvl2) || tlvl2.Equal(tlvl1) {
+		FailWithError(t, "TestTLVListEqual",
+			fmt.Errorf("lists of different lengths should not be Equal"))
+	}
+
+	var nilList *List
+	if !nilList.Equal(nil) || nilList.Equal(tlvl1) || tlvl1.Equal(nil) {
+		FailWithError(t, "TestTLVListEqual",
+			fmt.Errorf("nil lists compared incorrectly"))
+	}
+}
+
+func TestTLVListAppend(t *testing.T) {
+	tlvl1 := NewList()
+	tlvl1.Add(TypeTest1, []byte("foo bar"))
+	tlvl1.Add(TypeTest2, []byte("baz quux"))
+	tlvl2 := NewList()
+	tlvl2.Add(TypeTest3, []byte("gophers are everywhere!"))
+	tlvl2.Add(TypeTest4, []byte("goodbye, cruel world"))
+
+	tlvl1.Append(tlvl2)
+	if tlvl1.Length() != 4 || tlvl2.Length() != 2 {
+		FailWithError(t, "TestTLVListAppend",
+			fmt.Errorf("lengths %d and %d, expected 4 and 2", tlvl1.Length(), tlvl2.Length()))
+	}
+	var types []byte
+	tlvl1.Each(func(tlv TLV) bool {
+		types = append(types, tlv.Type())
+		return true
+	})
+	if !bytes.Equal(types, []byte{TypeTest1, TypeTest2, TypeTest3, TypeTest4}) {
+		FailWithError(t, "TestTLVListAppend",
+			fmt.Errorf("types %v out of order", types))
+	}
+
+	tlvl1.Append(tlvl1)
+	if tlvl1.Length() != 8 {
+		FailWithError(t, "TestTLVListAppend",
+			fmt.Errorf("self append gave %d records, expected 8", tlvl1.Length()))
+	}
+}
+
+func TestTLVListAppendClone(t *testing.T) {
+	tlvl1 := NewList()
+	tlvl1.Add(TypeTest1, []byte("foo bar"))
+	tlvl2 := NewList()
+	tlvl2.Add(TypeTest2, []byte("baz quux"))
+
+	tlvl1.AppendClone(tlvl2)
+	if tlvl1.Length() != 2 {
+		FailWithError(t, "TestTLVListAppendClone",
+			fmt.Errorf("%d records, expected 2", tlvl1.Length()))
+	}
+
+	tmpTLV, err := tlvl1.Get(TypeTest2)
+	if err != nil {
+		FailWithError(t, "TestTLVListAppendClone", err)
+	}
+	copy(tmpTLV.Value(), "XXX")
+	if srcTLV, err := tlvl2.Get(TypeTest2); err != nil {
+		FailWithError(t, "TestTLVListAppendClone", err)
+	} else if !Equal(srcTLV, New(TypeTest2, []byte("baz quux"))) {
+		FailWithError(t, "TestTLVListAppendClone",
+			fmt.Errorf("appended clone shares a buffer with the source"))
+	}
+}
+
+func TestTLVListSize(t *testing.T) {
+	tlvl := NewList()
+	if tlvl.Size() != 0 {
+		FailWithError(t, "TestTLVListSize",
+			fmt.Errorf("empty list size %d", tlvl.Size()))
+	}
+
+	tlvl.Add(TypeTest1, []byte("foo bar"))
+	tlvl.Add(TypeTest2, nil)
+	tlvl.Add(TypeTest3, []byte("gophers are everywhere!"))
+
+	buf := new(bytes.Buffer)
+	if err := tlvl.Write(buf); err != nil {
+		FailWithError(t, "TestTLVListSize", err)
+	}
+	if tlvl.Size() != buf.Len() {
+		FailWithError(t, "TestTLVListSize",
+			fmt.Errorf("size %d, wrote %d bytes", tlvl.Size(), buf.Len()))
+	}
+
+	tlv := New(TypeTest1, []byte("foo bar"))
+	if data, err := ToBytes(tlv); err != nil {
+		FailWithError(t, "TestTLVListSize", err)
+	} else if Size(tlv) != len(data) || Size(tlv) != 5+len("foo bar") {
+		FailWithError(t, "TestTLVListSize",
+			fmt.Errorf("object size %d, encoded %d bytes", Size(tlv), len(data)))
+	}
+}
+
+func TestTLVListAddUnique(t *testing.T) {
+	tlvl := NewList()
+	if err := tlvl.AddUnique(TypeTest2, []byte("foo bar")); err != nil {
+		FailWithError(t, "TestTLVListAddUnique", err)
+	}
+
+	err := tlvl.AddUnique(TypeTest2, []byte("baz quux"))
+	if !errors.Is(err, ErrDuplicateType) {
+		FailWithError(t, "TestTLVListAddUnique",
+			fmt.Errorf("expected ErrDuplicateType, got %v", err))
+	} else if !strings.Contains(err.Error(), "0x01") {
+		FailWithError(t, "TestTLVListAddUnique",
+			fmt.Errorf("error %q does not name the type", err))
+	}
+	if tlvl.Length() != 1 {
+		FailWithError(t, "TestTLVListAddUnique",
+			fmt.Errorf("%d records, expected 1", tlvl.Length()))
+	}
+	if tmpTLV, err := tlvl.Get(TypeTest2); err != nil {
+		FailWithError(t, "TestTLVListAddUnique", err)
+	} else if !Equal(tmpTLV, New(TypeTest2, []byte("foo bar"))) {
+		FailWithError(t, "TestTLVListAddUnique", errNoMatch)
+	}
+}
+
+func TestTLVListSet(t *testing.T) {
+	tlvl := NewList()
+	tlvl.Add(TypeTest1, []byte("foo bar"))
+
+	if tlvl.Set(TypeTest2, []byte("baz quux")) {
+		FailWithError(t, "TestTLVListSet",
+			fmt.Errorf("missing type reported as replaced"))
+	}
+	tlvl.Add(TypeTest3, []byte("gophers are everywhere!"))
+	if !tlvl.Set(TypeTest2, []byte("goodbye, cruel world")) {
+		FailWithError(t, "TestTLVListSet",
+			fmt.Errorf("existing type reported as inserted"))
+	}
+
+	expected := NewList()
+	expected.Add(TypeTest1, []byte("foo bar"))
+	expected.Add(TypeTest2, []byte("goodbye, cruel world"))
+	expected.Add(TypeTest3, []byte("gophers are everywhere!"))
+	if !tlvl.Equal(expected) {
+		FailWithError(t, "TestTLVListSet",
+			fmt.Errorf("got\n%s\nexpected\n%s", tlvl, expected))
+	}
+}
+
+func TestTLVListContains(t *testing.T) {
+	tlvl := NewList()
+	tlvl.Add(TypeTest1, []byte("foo bar"))
+	tlvl.Add(TypeTest1, []byte("baz quux"))
+	tlvl.Add(TypeTest2, []byte("gophers are everywhere!"))
+
+	if !tlvl.Contains(New(TypeTest1, []byte("baz quux"))) {
+		FailWithError(t, "TestTLVListContains",
+			fmt.Errorf("present object not found"))
+	}
+	if tlvl.Contains(New(TypeTest2, []byte("baz quux"))) {
+		FailWithError(t, "TestTLVListContains",
+			fmt.Errorf("absent object found"))
+	}
+
+	if tmpTLV, err := tlvl.FindByValue(TypeTest1, []byte("baz quux")); err != nil {
+		FailWithError(t, "TestTLVListContains", err)
+	} else if !Equal(tmpTLV, New(TypeTest1, []byte("baz quux"))) {
+		FailWithError(t, "TestTLVListContains", errNoMatch)
+	}
+	if _, err := tlvl.FindByValue(TypeTest1, []byte("goodbye, cruel world")); err != ErrTypeNotFound {
+		FailWithError(t, "TestTLVListContains",
+			fmt.Errorf("expected ErrTypeNotFound, got %v", err))
+	}
+}
+
+func TestTLVListSortByType(t *testing.T) {
+	tlvl := NewList()
+	tlvl.Add(TypeTest3, []byte("first three"))
+	tlvl.Add(TypeTest1, []byte("first one"))
+	tlvl.Add(TypeTest6, nil)
+	tlvl.Add(TypeTest3, []byte("second three"))
+	tlvl.Add(TypeTest2, nil)
+	tlvl.Add(TypeTest1, []byte("second one"))
+
+	tlvl.SortByType()
+
+	expected := NewList()
+	expected.Add(TypeTest1, []byte("first one"))
+	expected.Add(TypeTest1, []byte("second one"))
+	expected.Add(TypeTest2, nil)
+	expected.Add(TypeTest3, []byte("first three"))
+	expected.Add(TypeTest3, []byte("second three"))
+	expected.Add(TypeTest6, nil)
+	if !tlvl.Equal(expected) {
+		FailWithError(t, "TestTLVListSortByType",
+			fmt.Errorf("got\n%s\nexpected\n%s", tlvl, expected))
+	}
+}
+
+func TestTLVListReverse(t *testing.T) {
+	tlvl := NewList()
+	for _, typ := range []byte{TypeTest1, TypeTest2, TypeTest3, TypeTest4} {
+		tlvl.Add(typ, nil)
+	}
+
+	tlvl.Reverse()
+
+	var types []byte
+	tlvl.Each(func(tlv TLV) bool {
+		types = append(types, tlv.Type())
+		return true
+	})
+	if !bytes.Equal(types, []byte{TypeTest4, TypeTest3, TypeTest2, TypeTest1}) {
+		FailWithError(t, "TestTLVListReverse",
+			fmt.Errorf("types %v not reversed", types))
+	}
+
+	empty := NewList()
+	empty.Reverse()
+	if empty.Length() != 0 {
+		FailWithError(t, "TestTLVListReverse",
+			fmt.Errorf("empty list changed"))
+	}
+}
+
+func TestTLVListSlice(t *testing.T) {
+	tlvl := NewList()
+	tlvl.Add(TypeTest2, []byte("baz quux"))
+	tlvl.Add(TypeTest1, []byte("foo bar"))
+	tlvl.Add(TypeTest2, nil)
+
+	objs := tlvl.ToSlice()
+	if len(objs) != 3 {
+		FailWithError(t, "TestTLVListSlice",
+			fmt.Errorf("%d objects, expected 3", len(objs)))
+	}
+	if !ListFromSlice(objs).Equal(tlvl) {
+		FailWithError(t, "TestTLVListSlice", errNoMatch)
+	}
+	if ListFromSlice(nil).Length() != 0 || len(NewList().ToSlice()) != 0 {
+		FailWithError(t, "TestTLVListSlice",
+			fmt.Errorf("empty conversions should be empty"))
+	}
+}
+
+func TestTLVError(t *testing.T) {
+	data, err := ToBytes(New(TypeTest1, []byte("foo bar")))
+	if err != nil {
+		FailWithError(t, "TestTLVError", err)
+	}
+
+	_, err = ReadObject(bytes.NewReader(data[:9]))
+	var te *TLVError
+	if !errors.As(err, &te) {
+		FailWithError(t, "TestTLVError",
+			fmt.Errorf("expected *TLVError, got %T", err))
+	} else if te.Op != "read" || te.Offset != 9 {
+		FailWithError(t, "TestTLVError",
+			fmt.Errorf("op %q offset %d, expected read at 9", te.Op, te.Offset))
+	}
+	if !errors.Is(err, ErrTLVRead) || !errors.Is(err, io.ErrUnexpectedEOF) {
+		FailWithError(t, "TestTLVError",
+			fmt.Errorf("%v should match ErrTLVRead and io.ErrUnexpectedEOF", err))
+	} else if errors.Is(err, ErrTLVWrite) {
+		FailWithError(t, "TestTLVError",
+			fmt.Errorf("%v should not match ErrTLVWrite", err))
+	}
+
+	stream := append(append([]byte(nil), data...), data[:3]...)
+	_, err = Read(bytes.NewReader(stream))
+	if !errors.As(err, &te) {
+		FailWithError(t, "TestTLVError",
+			fmt.Errorf("expected *TLVError, got %T", err))
+	} else if te.Offset != int64(len(stream)) {
+		FailWithError(t, "TestTLVError",
+			fmt.Errorf("offset %d, expected %d", te.Offset, len(stream)))
+	} else if !errors.Is(err, ErrTLVRead) {
+		FailWithError(t, "TestTLVError",
+			fmt.Errorf("%v should match ErrTLVRead", err))
+	}
+}
+
+func TestTLVListGetValue(t *testing.T) {
+	tlvl := NewList()
+	tlvl.Add(TypeTest1, []byte("foo bar"))
+
+	if val, err := tlvl.GetValue(TypeTest1); err != nil {
+		FailWithError(t, "TestTLVListGetValue", err)
+	} else if string(val) != "foo bar" {
+		FailWithError(t, "TestTLVListGetValue", errNoMatch)
+	}
+
+	val, err := tlvl.GetValueCopy(TypeTest1)
+	if err != nil {
+		FailWithError(t, "TestTLVListGetValue", err)
+	}
+	copy(val, "XXX")
+	if val, _ := tlvl.GetValue(TypeTest1); string(val) != "foo bar" {
+		FailWithError(t, "TestTLVListGetValue",
+			fmt.Errorf("value modified through copy"))
+	}
+
+	if _, err := tlvl.GetValue(TypeTest2); err != ErrTypeNotFound {
+		FailWithError(t, "TestTLVListGetValue",
+			fmt.Errorf("expected ErrTypeNotFound, got %v", err))
+	}
+	if _, err := tlvl.GetValueCopy(TypeTest2); err != ErrTypeNotFound {
+		FailWithError(t, "TestTLVListGetValue",
+			fmt.Errorf("expected ErrTypeNotFound, got %v", err))
+	}
+}
+
+func TestTLVListRemoveFirstLast(t *testing.T) {
+	tlv1 := New(TypeTest1, []byte("foo bar"))
+	tlv2 := New(TypeTest1, []byte("baz quux"))
+	tlv3 := New(TypeTest1, []byte("goodbye, cruel world"))
+	tlvl := NewList()
+	tlvl.AddObject(tlv1)
+	tlvl.AddObject(tlv2)
+	tlvl.AddObject(tlv3)
+
+	if !tlvl.RemoveFirst(TypeTest1) {
+		FailWithError(t, "TestTLVListRemoveFirstLast",
+			fmt.Errorf("record not removed"))
+	}
+	if tlvl.Length() != 2 || tlvl.Contains(tlv1) {
+		FailWithError(t, "TestTLVListRemoveFirstLast",
+			fmt.Errorf("first record should be removed"))
+	}
+
+	if !tlvl.RemoveLast(TypeTest1) {
+		FailWithError(t, "TestTLVListRemoveFirstLast",
+			fmt.Errorf("record not removed"))
+	}
+	if tlvl.Length() != 1 || tlvl.Contains(tlv3) || !tlvl.Contains(tlv2) {
+		FailWithError(t, "TestTLVListRemoveFirstLast",
+			fmt.Errorf("last record should be removed"))
+	}
+
+	if tlvl.RemoveFirst(TypeTest2) || tlvl.RemoveLast(TypeTest2) {
+		FailWithError(t, "TestTLVListRemoveFirstLast",
+			fmt.Errorf("missing type reported as removed"))
+	}
+}
+
+func TestTLVListMerge(t *testing.T) {
+	tlvl := NewList()
+	tlvl.Add(TypeTest1, []byte("frag"))
+	tlvl.Add(TypeTest2, []byte("baz quux"))
+	tlvl.Add(TypeTest1, []byte("men"))
+	tlvl.Add(TypeTest1, []byte("ted"))
+
+	concat := func(a, b []byte) []byte {
+		return append(append([]byte(nil), a...), b...)
+	}
+	if !tlvl.Merge(TypeTest1, concat) {
+		FailWithError(t, "TestTLVListMerge",
+			fmt.Errorf("fragments not merged"))
+	}
+
+	expected := NewList()
+	expected.Add(TypeTest1, []byte("fragmented"))
+	expected.Add(TypeTest2, []byte("baz quux"))
+	if !tlvl.Equal(expected) {
+		FailWithError(t, "TestTLVListMerge",
+			fmt.Errorf("got\n%s\nexpected\n%s", tlvl, expected))
+	}
+
+	if tlvl.Merge(TypeTest1, concat) || tlvl.Merge(TypeTest3, concat) {
+		FailWithError(t, "TestTLVListMerge",
+			fmt.Errorf("nothing should be merged"))
+	}
+
+	wide := NewList()
+	wide.AddObject(NewTypeU32(0x1200|TypeTest1, []byte("wide")))
+	wide.Add(TypeTest1, []byte("frag"))
+	wide.AddObject(NewTypeU32(0x1200|TypeTest1, []byte("wider")))
+	wide.Add(TypeTest1, []byte("mented"))
+	if !wide.Merge(TypeTest1, concat) {
+		FailWithError(t, "TestTLVListMerge",
+			fmt.Errorf("fragments beside wide types not merged"))
+	}
+	expected = NewList()
+	expected.AddObject(NewTypeU32(0x1200|TypeTest1, []byte("wide")))
+	expected.Add(TypeTest1, []byte("fragmented"))
+	expected.AddObject(NewTypeU32(0x1200|TypeTest1, []byte("wider")))
+	if !wide.Equal(expected) {
+		FailWithError(t, "TestTLVListMerge",
+			fmt.Errorf("got\n%s\nexpected\n%s", wide, expected))
+	}
+}
+
+func TestTLVListClear(t *testing.T) {
+	tlvl := NewList()
+	if !tlvl.IsEmpty() {
+		FailWithError(t, "TestTLVListClear", fmt.Errorf("new list not empty"))
+	}
+	tlvl.Add(TypeTest1, []byte("foo bar"))
+	tlvl.Add(TypeTest2, []byte("baz quux"))
+	if tlvl.IsEmpty() {
+		FailWithError(t, "TestTLVListClear", fmt.Errorf("list with objects is empty"))
+	}
+
+	tlvl.Clear()
+	if tlvl.Length() != 0 || !tlvl.IsEmpty() {
+		FailWithError(t, "TestTLVListClear",
+			fmt.Errorf("cleared list has %d objects", tlvl.Length()))
+	}
+
+	tlvl.Add(TypeTest3, []byte("goodbye, cruel world"))
+	if tlvl.Length() != 1 {
+		FailWithError(t, "TestTLVListClear",
+			fmt.Errorf("list has %d objects after Add, expected 1", tlvl.Length()))
+	} else if val, err := tlvl.GetValue(TypeTest3); err != nil {
+		FailWithError(t, "TestTLVListClear", err)
+	} else if string(val) != "goodbye, cruel world" {
+		FailWithError(t, "TestTLVListClear", errNoMatch)
+	}
+}
+
+func TestTLVListAt(t *testing.T) {
+	tlvl := NewList()
+	tlvl.Add(TypeTest1, []byte("foo bar"))
+	tlvl.Add(TypeTest2, []byte("baz quux"))
+	tlvl.Add(TypeTest1, []byte("goodbye, cruel world"))
+
+	tests := []struct {
+		i        int
+		expected string
+	}{
+		{0, "foo bar"},
+		{1, "baz quux"},
+		{2, "goodbye, cruel world"},
+		{-1, "goodbye, cruel world"},
+		{-3, "foo bar"},
+	}
+	for _, test := range tests {
+		tlv, err := tlvl.At(test.i)
+		if err != nil {
+			FailWithError(t, "TestTLVListAt", err)
+		} else if string(tlv.Value()) != test.expected {
+			FailWithError(t, "TestTLVListAt",
+				fmt.Errorf("At(%d) = %q, expected %q", test.i, tlv.Value(), test.expected))
+		}
+	}
+
+	for _, i := range []int{3, -4} {
+		if _, err := tlvl.At(i); err != ErrIndexOutOfRange {
+			FailWithError(t, "TestTLVListAt",
+				fmt.Errorf("At(%d): expected ErrIndexOutOfRange, got %v", i, err))
+		}
+	}
+	if _, err := NewList().At(0); err != ErrIndexOutOfRange {
+		FailWithError(t, "TestTLVListAt",
+			fmt.Errorf("empty list: expected ErrIndexOutOfRange, got %v", err))
+	}
+}
+
+func TestReadAll(t *testing.T) {
+	tlvl := NewList()
+	tlvl.Add(TypeTest1, []byte("foo bar"))
+	tlvl.Add(TypeTest2, []byte("baz quux"))
+	block := new(bytes.Buffer)
+	if err := tlvl.Write(block); err != nil {
+		FailWithError(t, "TestReadAll", err)
+	}
+
+	rtlvl, n, err := ReadAll(bytes.NewReader(block.Bytes()))
+	if err != nil {
+		FailWithError(t, "TestReadAll", err)
+	} else if n != int64(block.Len()) {
+		FailWithError(t, "TestReadAll",
+			fmt.Errorf("%d bytes consumed, expected %d", n, block.Len()))
+	} else if !rtlvl.Equal(tlvl) {
+		FailWithError(t, "TestReadAll", errNoMatch)
+	}
+
+	// A frame holding the block followed by a trailer that isn't a TLV object.
+	frame := append(append([]byte(nil), block.Bytes()...), "END"...)
+	rtlvl, n, err = ReadAll(bytes.NewReader(frame))
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		FailWithError(t, "TestReadAll",
+			fmt.Errorf("expected io.ErrUnexpectedEOF, got %v", err))
+	} else if n != int64(block.Len()) {
+		FailWithError(t, "TestReadAll",
+			fmt.Errorf("%d bytes consumed, expected %d", n, block.Len()))
+	} else if string(frame[n:]) != "END" {
+		FailWithError(t, "TestReadAll",
+			fmt.Errorf("trailer %q, expected \"END\"", frame[n:]))
+	} else if !rtlvl.Equal(tlvl) {
+		FailWithError(t, "TestReadAll", errNoMatch)
+	}
+}
+
+func TestTLVListAddValidated(t *testing.T) {
+	tlvl := NewList()
+	if err := tlvl.AddValidated(TypeTest2, []byte("foo bar")); err != nil {
+		FailWithError(t, "TestTLVListAddValidated", err)
+	}
+
+	tlvl.SetValidator(func(typ byte, val []byte) error {
+		if typ == TypeTest2 && len(val) != 4 {
+			return ErrInvalidLength
+		}
+		return nil
+	})
+	if err := tlvl.AddValidated(TypeTest2, []byte("baz quux")); err != ErrInvalidLength {
+		FailWithError(t, "TestTLVListAddValidated",
+			fmt.Errorf("expected ErrInvalidLength, got %v", err))
+	}
+	if err := tlvl.AddObjectValidated(NewUint16(TypeTest2, 1)); err != ErrInvalidLength {
+		FailWithError(t, "TestTLVListAddValidated",
+			fmt.Errorf("expected ErrInvalidLength, got %v", err))
+	}
+	if err := tlvl.AddValidated(TypeTest2, []byte{1, 2, 3, 4}); err != nil {
+		FailWithError(t, "TestTLVListAddValidated", err)
+	}
+	if err := tlvl.AddObjectValidated(New(TypeTest1, []byte("gophers are everywhere!"))); err != nil {
+		FailWithError(t, "TestTLVListAddValidated", err)
+	}
+	if tlvl.Length() != 3 {
+		FailWithError(t, "TestTLVListAddValidated",
+			fmt.Errorf("%d objects, expected 3", tlvl.Length()))
+	}
+
+	if err := tlvl.Clone().AddValidated(TypeTest2, nil); err != ErrInvalidLength {
+		FailWithError(t, "TestTLVListAddValidated",
+			fmt.Errorf("clone: expected ErrInvalidLength, got %v", err))
+	}
+}
+
+func TestFromBytesN(t *testing.T) {
+	first := New(TypeTest1, []byte("foo bar"))
+	second := New(TypeTest2, []byte("baz quux"))
+	data, err := AppendEncoded(nil, first)
+	if err != nil {
+		FailWithError(t, "TestFromBytesN", err)
+	}
+	if data, err = AppendEncoded(data, second); err != nil {
+		FailWithError(t, "TestFromBytesN", err)
+	}
+
+	tlv, n, err := FromBytesN(data)
+	if err != nil {
+		FailWithError(t, "TestFromBytesN", err)
+	} else if !Equal(tlv, first) {
+		FailWithError(t, "TestFromBytesN", errNoMatch)
+	} else if n != Size(first) {
+		FailWithError(t, "TestFromBytesN",
+			fmt.Errorf("%d bytes consumed, expected %d", n, Size(first)))
+	}
+
+	tlv, m, err := FromBytesN(data[n:])
+	if err != nil {
+		FailWithError(t, "TestFromBytesN", err)
+	} else if !Equal(tlv, second) {
+		FailWithError(t, "TestFromBytesN", errNoMatch)
+	} else if n+m != len(data) {
+		FailWithError(t, "TestFromBytesN",
+			fmt.Errorf("%d bytes consumed, expected %d", n+m, len(data)))
+	}
+
+	if _, _, err = FromBytesN(data[n+m:]); err != io.EOF {
+		FailWithError(t, "TestFromBytesN",
+			fmt.Errorf("expected io.EOF, got %v", err))
+	}
+}
+
+// chunkWriter accepts at most size bytes per call, and fails once limit bytes are written.
+type chunkWriter struct {
+	buf   bytes.Buffer
+	size  int
+	limit int
+}
+
+func (cw *chunkWriter) Write(p []byte) (int, error) {
+	if cw.limit > 0 && cw.buf.Len() >= cw.limit {
+		return 0, io.ErrClosedPipe
+	}
+	if len(p) > cw.size {
+		p = p[:cw.size]
+	}
+	return cw.buf.Write(p)
+}
+
+func TestWriteObjectShortWrites(t *testing.T) {
+	tlv := New(TypeTest3, []byte("gophers are everywhere!"))
+	expected, err := ToBytes(tlv)
+	if err != nil {
+		FailWithError(t, "TestWriteObjectShortWrites", err)
+	}
+
+	cw := &chunkWriter{size: 3}
+	if err = WriteObject(tlv, cw); err != nil {
+		FailWithError(t, "TestWriteObjectShortWrites", err)
+	} else if !bytes.Equal(cw.buf.Bytes(), expected) {
+		FailWithError(t, "TestWriteObjectShortWrites",
+			fmt.Errorf("wrote % x, expected % x", cw.buf.Bytes(), expected))
+	}
+
+	cw = &chunkWriter{size: 3}
+	if err = NewEncoder(cw).Encode(tlv); err != nil {
+		FailWithError(t, "TestWriteObjectShortWrites", err)
+	} else if !bytes.Equal(cw.buf.Bytes(), expected) {
+		FailWithError(t, "TestWriteObjectShortWrites",
+			fmt.Errorf("encoded % x, expected % x", cw.buf.Bytes(), expected))
+	}
+
+	cw = &chunkWriter{size: 3, limit: 9}
+	err = WriteObject(tlv, cw)
+	var te *TLVError
+	if !errors.As(err, &te) {
+		FailWithError(t, "TestWriteObjectShortWrites",
+			fmt.Errorf("expected *TLVError, got %v", err))
+	} else if te.Op != "write" || te.Offset != int64(cw.buf.Len()) {
+		FailWithError(t, "TestWriteObjectShortWrites",
+			fmt.Errorf("op %q offset %d, expected write at %d", te.Op, te.Offset, cw.buf.Len()))
+	} else if !errors.Is(err, io.ErrClosedPipe) || !errors.Is(err, ErrTLVWrite) {
+		FailWithError(t, "TestWriteObjectShortWrites",
+			fmt.Errorf("%v should match io.ErrClosedPipe and ErrTLVWrite", err))
+	}
+}
+
+func TestEqualTrimmed(t *testing.T) {
+	padded := New(TypeTest5, []byte{0x0a, 0x00})
+	unpadded := New(TypeTest5, []byte{0x0a})
+	if Equal(padded, unpadded) {
+		FailWithError(t, "TestEqualTrimmed", fmt.Errorf("padded values should differ under Equal"))
+	} else if !EqualTrimmed(padded, unpadded) {
+		FailWithError(t, "TestEqualTrimmed", fmt.Errorf("padded values should match under EqualTrimmed"))
+	}
+
+	if EqualTrimmed(padded, New(TypeTest4, []byte{0x0a})) {
+		FailWithError(t, "TestEqualTrimmed", fmt.Errorf("types should differ"))
+	} else if EqualTrimmed(padded, New(TypeTest5, []byte{0x00, 0x0a})) {
+		FailWithError(t, "TestEqualTrimmed", fmt.Errorf("leading zeros should not be trimmed"))
+	} else if !EqualTrimmed(New(TypeTest5, []byte{0, 0}), New(TypeTest5, nil)) {
+		FailWithError(t, "TestEqualTrimmed", fmt.Errorf("all-zero value should match an empty one"))
+	}
+}
+
+func TestCompareType(t *testing.T) {
+	if CompareType(New(0x01, nil), New(0xff, nil)) != -1 ||
+		CompareType(New(0xff, nil), New(0x80, nil)) != 1 ||
+		CompareType(New(0x80, []byte("foo bar")), New(0x80, nil)) != 0 {
+		FailWithError(t, "TestCompareType", fmt.Errorf("types not compared as unsigned"))
+	}
+
+	tlvl := NewList()
+	tlvl.Add(0xff, nil)
+	tlvl.Add(0x01, nil)
+	tlvl.Add(0x80, nil)
+	tlvl.SortByType()
+
+	var types []byte
+	tlvl.Each(func(tlv TLV) bool {
+		types = append(types, tlv.Type())
+		return true
+	})
+	if !bytes.Equal(types, []byte{0x01, 0x80, 0xff}) {
+		FailWithError(t, "TestCompareType",
+			fmt.Errorf("sorted types % x, expected 01 80 ff", types))
+	}
+}
+
+func TestSetValue(t *testing.T) {
+	tlv := NewTypeU32(0x0102, []byte("foo bar"))
+	updated, err := SetValue(tlv, []byte("gophers are everywhere!"))
+	if err != nil {
+		FailWithError(t, "TestSetValue", err)
+	} else if TypeU32(updated) != 0x0102 {
+		FailWithError(t, "TestSetValue",
+			fmt.Errorf("type 0x%x, expected 0x0102", TypeU32(updated)))
+	} else if updated.Length() != 23 || string(updated.Value()) != "gophers are everywhere!" {
+		FailWithError(t, "TestSetValue",
+			fmt.Errorf("length %d value %q", updated.Length(), updated.Value()))
+	} else if string(tlv.Value()) != "foo bar" {
+		FailWithError(t, "TestSetValue", fmt.Errorf("original object was modified"))
+	}
+
+	if updated, err = SetValue(tlv, nil); err != nil {
+		FailWithError(t, "TestSetValue", err)
+	} else if updated.Length() != 0 {
+		FailWithError(t, "TestSetValue",
+			fmt.Errorf("length %d, expected 0", updated.Length()))
+	}
+}
+
+func TestTLVListTypeSet(t *testing.T) {
+	tlvl := NewList()
+	tlvl.Add(TypeTest1, []byte("foo bar"))
+	tlvl.Add(TypeTest3, []byte("baz quux"))
+	tlvl.Add(TypeTest1, []byte("goodbye, cruel world"))
+	tlvl.Add(TypeTest3, nil)
+
+	set := tlvl.TypeSet()
+	if len(set) != 2 {
+		FailWithError(t, "TestTLVListTypeSet",
+			fmt.Errorf("%d types, expected 2", len(set)))
+	}
+	for _, typ := range []byte{TypeTest1, TypeTest3} {
+		if _, ok := set[typ]; !ok {
+			FailWithError(t, "TestTLVListTypeSet",
+				fmt.Errorf("type %d missing from set", typ))
+		}
+	}
+	if len(NewList().TypeSet()) != 0 {
+		FailWithError(t, "TestTLVListTypeSet", fmt.Errorf("empty list has types"))
+	}
+}
+
+func TestReadVersioned(t *testing.T) {
+	tlvl := NewList()
+	tlvl.Add(TypeTest1, []byte("foo bar"))
+	tlvl.Add(TypeTest2, []byte("baz quux"))
+	body := new(bytes.Buffer)
+	if err := tlvl.Write(body); err != nil {
+		FailWithError(t, "TestReadVersioned", err)
+	}
+
+	for _, version := range []byte{1, 2} {
+		data := append([]byte{version}, body.Bytes()...)
+		v, rtlvl, err := ReadVersioned(bytes.NewReader(data))
+		if err != nil {
+			FailWithError(t, "TestReadVersioned", err)
+		} else if v != version {
+			FailWithError(t, "TestReadVersioned",
+				fmt.Errorf("version %d, expected %d", v, version))
+		} else if !rtlvl.Equal(tlvl) {
+			FailWithError(t, "TestReadVersioned", errNoMatch)
+		}
+	}
+
+	if _, _, err := ReadVersioned(bytes.NewReader(nil)); err != io.EOF {
+		FailWithError(t, "TestReadVersioned",
+			fmt.Errorf("expected io.EOF, got %v", err))
+	}
+}
+
+func TestTLVListRemoveTypes(t *testing.T) {
+	tlvl := NewList()
+	tlvl.Add(TypeTest1, []byte("foo bar"))
+	tlvl.Add(TypeTest2, []byte("baz quux"))
+	tlvl.Add(TypeTest3, []byte("goodbye, cruel world"))
+	tlvl.Add(TypeTest1, nil)
+	tlvl.Add(TypeTest4, []byte("gophers are everywhere!"))
+	tlvl.Add(TypeTest5, nil)
+
+	if n := tlvl.RemoveTypes(TypeTest1, TypeTest3, TypeTest5); n != 4 {
+		FailWithError(t, "TestTLVListRemoveTypes",
+			fmt.Errorf("%d objects removed, expected 4", n))
+	}
+
+	expected := NewList()
+	expected.Add(TypeTest2, []byte("baz quux"))
+	expected.Add(TypeTest4, []byte("gophers are everywhere!"))
+	if !tlvl.Equal(expected) {
+		FailWithError(t, "TestTLVListRemoveTypes",
+			fmt.Errorf("got\n%s\nexpected\n%s", tlvl, expected))
+	}
+
+	if n := tlvl.RemoveTypes(); n != 0 {
+		FailWithError(t, "TestTLVListRemoveTypes",
+			fmt.Errorf("%d objects removed with no types", n))
+	}
+}
+
+// customTLV is a TLV implementation whose Length is set independently of its Value.
+type customTLV struct {
+	typ    byte
+	length int32
+	val    []byte
+}
+
+func (c *customTLV) Type() byte    { return c.typ }
+func (c *customTLV) Length() int32 { return c.length }
+func (c *customTLV) Value() []byte { return c.val }
+
+func TestNormalize(t *testing.T) {
+	bad := &customTLV{typ: TypeTest2, length: 42, val: []byte("foo bar")}
+	if err := Validate(bad); !errors.Is(err, ErrInvalidLength) {
+		FailWithError(t, "TestNormalize",
+			fmt.Errorf("expected ErrInvalidLength, got %v", err))
+	}
+	if err := WriteObject(bad, ioutil.Discard); !errors.Is(err, ErrInvalidLength) {
+		FailWithError(t, "TestNormalize",
+			fmt.Errorf("write: expected ErrInvalidLength, got %v", err))
+	}
+
+	fixed := Normalize(bad)
+	if err := Validate(fixed); err != nil {
+		FailWithError(t, "TestNormalize", err)
+	} else if fixed.Type() != TypeTest2 || fixed.Length() != 7 || string(fixed.Value()) != "foo bar" {
+		FailWithError(t, "TestNormalize", fmt.Errorf("normalized to %s", fixed))
+	}
+
+	good := &customTLV{typ: TypeTest2, length: 7, val: []byte("foo bar")}
+	if err := Validate(good); err != nil {
+		FailWithError(t, "TestNormalize", err)
+	} else if Normalize(good) != TLV(good) {
+		FailWithError(t, "TestNormalize", fmt.Errorf("valid object should be returned as is"))
+	}
+
+	tlvl := NewList()
+	tlvl.AddObject(good)
+	if tlv, err := tlvl.Get(TypeTest2); err != nil {
+		FailWithError(t, "TestNormalize", err)
+	} else if !Equal(tlv, fixed) {
+		FailWithError(t, "TestNormalize", errNoMatch)
+	}
+	if n := tlvl.Remove(TypeTest2); n != 1 {
+		FailWithError(t, "TestNormalize",
+			fmt.Errorf("%d custom objects removed, expected 1", n))
+	}
+}
+
+func TestTLVListWriteOrdered(t *testing.T) {
+	tlvl := NewList()
+	tlvl.Add(TypeTest1, []byte("foo bar"))
+	tlvl.Add(TypeTest2, []byte("baz quux"))
+	tlvl.Add(TypeTest3, []byte("goodbye, cruel world"))
+	tlvl.Add(TypeTest2, []byte("gophers are everywhere!"))
+	tlvl.Add(TypeTest4, nil)
+	original := tlvl.Clone()
+
+	buf := new(bytes.Buffer)
+	if err := tlvl.WriteOrdered(buf, []byte{TypeTest3, TypeTest2, TypeTest3}); err != nil {
+		FailWithError(t, "TestTLVListWriteOrdered", err)
+	}
+	rtlvl, err := Read(buf)
+	if err != nil {
+		FailWithError(t, "TestTLVListWriteOrdered", err)
+	}
+
+	expected := NewList()
+	expected.Add(TypeTest3, []byte("goodbye, cruel world"))
+	expected.Add(TypeTest2, []byte("baz quux"))
+	expected.Add(TypeTest2, []byte("gophers are everywhere!"))
+	expected.Add(TypeTest1, []byte("foo bar"))
+	expected.Add(TypeTest4, nil)
+	if !rtlvl.Equal(expected) {
+		FailWithError(t, "TestTLVListWriteOrdered",
+			fmt.Errorf("got\n%s\nexpected\n%s", rtlvl, expected))
+	} else if !tlvl.Equal(original) {
+		FailWithError(t, "TestTLVListWriteOrdered", fmt.Errorf("list was modified"))
+	}
+}
+
+func TestTLVListFindByValuePrefix(t *testing.T) {
+	tlvl := NewList()
+	tlvl.Add(TypeTest1, []byte{10, 0, 0, 0})
+	tlvl.Add(TypeTest1, []byte{10, 1, 0, 0})
+	tlvl.Add(TypeTest1, []byte{192, 168, 0, 0})
+	tlvl.Add(TypeTest2, []byte{10, 0, 0, 1})
+	tlvl.Add(TypeTest1, []byte{10})
+
+	found := tlvl.FindByValuePrefix(TypeTest1, []byte{10})
+	if len(found) != 3 {
+		FailWithError(t, "TestTLVListFindByValuePrefix",
+			fmt.Errorf("%d objects found, expected 3", len(found)))
+	}
+	for _, tlv := range found {
+		if tlv.Type() != TypeTest1 || tlv.Value()[0] != 10 {
+			FailWithError(t, "TestTLVListFindByValuePrefix",
+				fmt.Errorf("unexpected match %s", tlv))
+		}
+	}
+
+	found = tlvl.FindByValuePrefix(TypeTest1, []byte{10, 1})
+	if len(found) != 1 || !bytes.Equal(found[0].Value(), []byte{10, 1, 0, 0}) {
+		FailWithError(t, "TestTLVListFindByValuePrefix",
+			fmt.Errorf("found %v, expected 0a010000", found))
+	}
+	if found = tlvl.FindByValuePrefix(TypeTest3, nil); len(found) != 0 {
+		FailWithError(t, "TestTLVListFindByValuePrefix",
+			fmt.Errorf("%d objects found for a missing type", len(found)))
+	}
+}
+
+func TestReadUntil(t *testing.T) {
+	section := NewList()
+	section.Add(TypeTest2, []byte("foo bar"))
+	section.Add(TypeTest3, []byte("baz quux"))
+
+	buf := new(bytes.Buffer)
+	section.Write(buf)
+	WriteObject(New(TypeTest1, nil), buf)
+	WriteObject(New(TypeTest4, []byte("gophers are everywhere!")), buf)
+	buf.WriteString("trailing")
+
+	tlvl, err := ReadUntil(buf, TypeTest1)
+	if err != nil {
+		FailWithError(t, "TestReadUntil", err)
+	} else if !tlvl.Equal(section) {
+		FailWithError(t, "TestReadUntil",
+			fmt.Errorf("got\n%s\nexpected\n%s", tlvl, section))
+	}
+
+	tlv, err := ReadObject(buf)
+	if err != nil {
+		FailWithError(t, "TestReadUntil", err)
+	} else if tlv.Type() != TypeTest4 {
+		FailWithError(t, "TestReadUntil",
+			fmt.Errorf("type %d after the sentinel, expected %d", tlv.Type(), TypeTest4))
+	} else if buf.String() != "trailing" {
+		FailWithError(t, "TestReadUntil", fmt.Errorf("%q left, expected \"trailing\"", buf.String()))
+	}
+
+	data := new(bytes.Buffer)
+	section.Write(data)
+	if tlvl, err = ReadUntil(data, TypeTest1); err != nil {
+		FailWithError(t, "TestReadUntil", err)
+	} else if !tlvl.Equal(section) {
+		FailWithError(t, "TestReadUntil", errNoMatch)
+	}
+
+	data.Reset()
+	section.Write(data)
+	raw := data.Bytes()[:data.Len()-1]
+	_, err = ReadUntil(bytes.NewReader(raw), TypeTest1)
+	var te *TLVError
+	if !errors.As(err, &te) {
+		FailWithError(t, "TestReadUntil", fmt.Errorf("expected a *TLVError, got %v", err))
+	} else if te.Offset != int64(len(raw)) {
+		FailWithError(t, "TestReadUntil",
+			fmt.Errorf("expected offset %d, got %d", len(raw), te.Offset))
+	}
+}
+
+func TestEqualByValue(t *testing.T) {
+	tlv := New(TypeTest2, []byte("foo bar"))
+	bad := &customTLV{typ: TypeTest2, length: 3, val: []byte("foo bar")}
+	if Equal(tlv, bad) {
+		FailWithError(t, "TestEqualByValue", fmt.Errorf("lengths differ, Equal should be false"))
+	} else if !EqualByValue(tlv, bad) {
+		FailWithError(t, "TestEqualByValue", fmt.Errorf("values match, EqualByValue should be true"))
+	}
+
+	if EqualByValue(tlv, &customTLV{typ: TypeTest3, length: 7, val: []byte("foo bar")}) {
+		FailWithError(t, "TestEqualByValue", fmt.Errorf("types differ"))
+	} else if EqualByValue(tlv, New(TypeTest2, []byte("baz quux"))) {
+		FailWithError(t, "TestEqualByValue", fmt.Errorf("values differ"))
+	} else if EqualByValue(tlv, nil) || !EqualByValue(nil, nil) {
+		FailWithError(t, "TestEqualByValue", fmt.Errorf("nil handling"))
+	}
+}
+
+func appendTestList() *List {
+	tlvl := NewList()
+	tlvl.Add(TypeTest1, []byte("foo bar"))
+	tlvl.Add(TypeTest2, nil)
+	tlvl.Add(TypeTest3, []byte("gophers are everywhere!"))
+	return tlvl
+}
+
+func TestTLVListAppendTo(t *testing.T) {
+	tlvl := appendTestList()
+	buf := new(bytes.Buffer)
+	if err := tlvl.Write(buf); err != nil {
+		FailWithError(t, "TestTLVListAppendTo", err)
+	}
+
+	prefix := []byte("frame:")
+	data, err := tlvl.AppendTo(append([]byte(nil), prefix...))
+	if err != nil {
+		FailWithError(t, "TestTLVListAppendTo", err)
+	} else if !bytes.HasPrefix(data, prefix) || !bytes.Equal(data[len(prefix):], buf.Bytes()) {
+		FailWithError(t, "TestTLVListAppendTo",
+			fmt.Errorf("appended % x, expected % x", data[len(prefix):], buf.Bytes()))
+	}
+	if data, err = NewList().AppendTo(nil); err != nil || len(data) != 0 {
+		FailWithError(t, "TestTLVListAppendTo",
+			fmt.Errorf("empty list appended %d bytes, err %v", len(data), err))
+	}
+
+	tlvl.objects.PushBack(NewTypeU32(0x1234, []byte("foo bar")))
+	if err = tlvl.Write(new(bytes.Buffer)); err != ErrTypeOverflow {
+		FailWithError(t, "TestTLVListAppendTo",
+			fmt.Errorf("Write: expected ErrTypeOverflow, got %v", err))
+	}
+	if data, err = tlvl.AppendTo(nil); err != ErrTypeOverflow {
+		FailWithError(t, "TestTLVListAppendTo",
+			fmt.Errorf("expected ErrTypeOverflow, got %v", err))
+	} else if !bytes.Equal(data, buf.Bytes()) {
+		FailWithError(t, "TestTLVListAppendTo",
+			fmt.Errorf("appended % x before the failing object, expected % x", data, buf.Bytes()))
+	}
+}
+
+func BenchmarkTLVListWrite(b *testing.B) {
+	tlvl := appendTestList()
+	buf := new(bytes.Buffer)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		if err := tlvl.Write(buf); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTLVListAppendTo(b *testing.B) {
+	tlvl := appendTestList()
+	var buf []byte
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var err error
+		if buf, err = tlvl.AppendTo(buf[:0]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestTLVListFindAll(t *testing.T) {
+	tlvl := NewList()
+	tlvl.Add(TypeTest1, []byte("foo bar"))
+	tlvl.Add(TypeTest2, []byte("goodbye, cruel world"))
+	tlvl.Add(TypeTest3, []byte("baz quux"))
+	tlvl.Add(TypeTest1, []byte("gophers are everywhere!"))
+
+	found := tlvl.FindAll(func(tlv TLV) bool { return tlv.Length() > 8 })
+	if len(found) != 2 {
+		FailWithError(t, "TestTLVListFindAll",
+			fmt.Errorf("%d objects found, expected 2", len(found)))
+	} else if string(found[0].Value()) != "goodbye, cruel world" ||
+		string(found[1].Value()) != "gophers are everywhere!" {
+		FailWithError(t, "TestTLVListFindAll", errNoMatch)
+	}
+
+	if found = tlvl.FindAll(func(TLV) bool { return false }); found == nil || len(found) != 0 {
+		FailWithError(t, "TestTLVListFindAll",
+			fmt.Errorf("expected an empty slice, got %v", found))
+	}
+}
+
+func TestTLVListWideTypes(t *testing.T) {
+	tlvl := NewList()
+	tlvl.AddObject(NewTypeU32(0x0101, []byte("wide")))
+	tlvl.AddObject(NewTypeU32(0x0001, []byte("narrow")))
+	buf := new(bytes.Buffer)
+	codec := NewCodec16()
+	if err := codec.Write(tlvl, buf); err != nil {
+		FailWithError(t, "TestTLVListWideTypes", err)
+	}
+	rtlvl, err := codec.Read(buf)
+	if err != nil {
+		FailWithError(t, "TestTLVListWideTypes", err)
+	}
+
+	if tlv, err := rtlvl.Get(0x01); err != nil {
+		FailWithError(t, "TestTLVListWideTypes", err)
+	} else if string(tlv.Value()) != "narrow" {
+		FailWithError(t, "TestTLVListWideTypes",
+			fmt.Errorf("Get(0x01) returned %s", tlv))
+	}
+	if n := rtlvl.Count(0x01); n != 1 {
+		FailWithError(t, "TestTLVListWideTypes",
+			fmt.Errorf("Count(0x01) is %d, expected 1", n))
+	}
+	if h := rtlvl.Histogram(); len(h) != 1 || h[0x01] != 1 {
+		FailWithError(t, "TestTLVListWideTypes",
+			fmt.Errorf("Histogram is %v, expected map[1:1]", h))
+	}
+	if rtlvl.Remove(0x01) != 1 || rtlvl.Length() != 1 {
+		FailWithError(t, "TestTLVListWideTypes",
+			fmt.Errorf("Remove(0x01) touched the wide type"))
+	}
+}
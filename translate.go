@@ -0,0 +1,44 @@
+package tlv
+
+import (
+	"fmt"
+	"io"
+)
+
+// TranslateStream copies TLV objects from r to w until r ends cleanly, replacing each type
+// found in remap with its mapped type. Values are streamed through rather than buffered.
+// An error names the zero-based index of the object being copied, and holds a *TLVError
+// whose offset is from the start of the stream.
+func TranslateStream(r io.Reader, w io.Writer, remap map[byte]byte) error {
+	cr := &countingReader{r: r}
+	for n := 0; ; n++ {
+		start := cr.n
+		typ, length, err := ReadHeader(cr)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			shiftOffset(err, start)
+			return fmt.Errorf("TLV object %d: %w", n, err)
+		}
+
+		if mapped, ok := remap[typ]; ok {
+			typ = mapped
+		}
+		if err = WriteHeader(w, typ, length); err != nil {
+			shiftOffset(err, start)
+			return fmt.Errorf("TLV object %d: %w", n, err)
+		}
+		fw := &fullWriter{w: w}
+		if _, err = io.CopyN(fw, cr, int64(length)); err != nil {
+			if fw.err != nil {
+				err = fw.wrap(err)
+				shiftOffset(err, start+headerSize)
+			} else if err == io.EOF {
+				err = &TLVError{Op: "read", Offset: cr.n, Err: io.ErrUnexpectedEOF}
+			} else {
+				err = &TLVError{Op: "read", Offset: cr.n, Err: err}
+			}
+			return fmt.Errorf("TLV object %d: %w", n, err)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package tlv
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestTranslateStream(t *testing.T) {
+	tlvl := NewList()
+	tlvl.Add(1, []byte("foo bar"))
+	tlvl.Add(2, []byte("gophers are everywhere!"))
+	in := new(bytes.Buffer)
+	if err := tlvl.Write(in); err != nil {
+		FailWithError(t, "TestTranslateStream", err)
+	}
+	data := in.Bytes()
+
+	out := new(bytes.Buffer)
+	if err := TranslateStream(bytes.NewReader(data), out, map[byte]byte{1: 9}); err != nil {
+		FailWithError(t, "TestTranslateStream", err)
+	}
+
+	rtlvl, err := Read(out)
+	if err != nil {
+		FailWithError(t, "TestTranslateStream", err)
+	}
+	expected := NewList()
+	expected.Add(9, []byte("foo bar"))
+	expected.Add(2, []byte("gophers are everywhere!"))
+	if !rtlvl.Equal(expected) {
+		FailWithError(t, "TestTranslateStream",
+			fmt.Errorf("got\n%s\nexpected\n%s", rtlvl, expected))
+	}
+
+	err = TranslateStream(bytes.NewReader(data[:len(data)-1]), io.Discard, nil)
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		FailWithError(t, "TestTranslateStream",
+			fmt.Errorf("expected io.ErrUnexpectedEOF, got %v", err))
+	}
+}
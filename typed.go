@@ -0,0 +1,23 @@
+package tlv
+
+// Get decodes the value of the first object in the TLVList matching the type.
+// It returns ErrTypeNotFound if there is none, or the error returned by decode.
+func Get[T any](tl *List, typ byte, decode func([]byte) (T, error)) (T, error) {
+	val, err := tl.GetValue(typ)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return decode(val)
+}
+
+// Typed pairs a TLV type with the function decoding its values.
+type Typed[T any] struct {
+	Type   byte
+	Decode func([]byte) (T, error)
+}
+
+// Get decodes the value of the first object in the TLVList matching the field's type, like Get.
+func (f Typed[T]) Get(tl *List) (T, error) {
+	return Get(tl, f.Type, f.Decode)
+}
@@ -0,0 +1,47 @@
+package tlv
+
+import (
+	"encoding/binary"
+	"fmt"
+	"testing"
+)
+
+func decodeInt(val []byte) (int, error) {
+	if len(val) != 8 {
+		return 0, ErrInvalidLength
+	}
+	return int(binary.BigEndian.Uint64(val)), nil
+}
+
+func decodeString(val []byte) (string, error) {
+	return string(val), nil
+}
+
+func TestGetTyped(t *testing.T) {
+	tlvl := NewList()
+	tlvl.AddObject(NewUint64(TypeTest1, 42))
+	tlvl.Add(TypeTest2, []byte("foo bar"))
+
+	n, err := Get(tlvl, TypeTest1, decodeInt)
+	if err != nil {
+		FailWithError(t, "TestGetTyped", err)
+	} else if n != 42 {
+		FailWithError(t, "TestGetTyped", fmt.Errorf("got %d, expected 42", n))
+	}
+
+	s, err := Typed[string]{Type: TypeTest2, Decode: decodeString}.Get(tlvl)
+	if err != nil {
+		FailWithError(t, "TestGetTyped", err)
+	} else if s != "foo bar" {
+		FailWithError(t, "TestGetTyped", fmt.Errorf("got %q, expected \"foo bar\"", s))
+	}
+
+	if _, err = Get(tlvl, TypeTest2, decodeInt); err != ErrInvalidLength {
+		FailWithError(t, "TestGetTyped",
+			fmt.Errorf("expected ErrInvalidLength, got %v", err))
+	}
+	if _, err = Get(tlvl, TypeTest3, decodeString); err != ErrTypeNotFound {
+		FailWithError(t, "TestGetTyped",
+			fmt.Errorf("expected ErrTypeNotFound, got %v", err))
+	}
+}
@@ -0,0 +1,121 @@
+package tlv
+
+import "encoding/binary"
+
+// NewUint16 returns a TLV object holding v encoded as 2 big-endian bytes
+func NewUint16(typ byte, v uint16) TLV {
+	val := make([]byte, 2)
+	binary.BigEndian.PutUint16(val, v)
+	return New(typ, val)
+}
+
+// NewUint32 returns a TLV object holding v encoded as 4 big-endian bytes
+func NewUint32(typ byte, v uint32) TLV {
+	val := make([]byte, 4)
+	binary.BigEndian.PutUint32(val, v)
+	return New(typ, val)
+}
+
+// NewUint64 returns a TLV object holding v encoded as 8 big-endian bytes
+func NewUint64(typ byte, v uint64) TLV {
+	val := make([]byte, 8)
+	binary.BigEndian.PutUint64(val, v)
+	return New(typ, val)
+}
+
+// NewString returns a TLV object holding the bytes of s
+func NewString(typ byte, s string) TLV {
+	return New(typ, []byte(s))
+}
+
+// Uint16 decodes a TLV object's value as a big-endian uint16.
+// It returns ErrInvalidLength if the value is not exactly 2 bytes.
+func Uint16(tlv TLV) (uint16, error) {
+	if len(tlv.Value()) != 2 {
+		return 0, ErrInvalidLength
+	}
+	return binary.BigEndian.Uint16(tlv.Value()), nil
+}
+
+// Uint32 decodes a TLV object's value as a big-endian uint32.
+// It returns ErrInvalidLength if the value is not exactly 4 bytes.
+func Uint32(tlv TLV) (uint32, error) {
+	if len(tlv.Value()) != 4 {
+		return 0, ErrInvalidLength
+	}
+	return binary.BigEndian.Uint32(tlv.Value()), nil
+}
+
+// Uint64 decodes a TLV object's value as a big-endian uint64.
+// It returns ErrInvalidLength if the value is not exactly 8 bytes.
+func Uint64(tlv TLV) (uint64, error) {
+	if len(tlv.Value()) != 8 {
+		return 0, ErrInvalidLength
+	}
+	return binary.BigEndian.Uint64(tlv.Value()), nil
+}
+
+// AddUint16 pushes an object holding v encoded as 2 big-endian bytes onto the TLVList.
+func (tl *List) AddUint16(typ byte, v uint16) {
+	tl.AddObject(NewUint16(typ, v))
+}
+
+// AddUint32 pushes an object holding v encoded as 4 big-endian bytes onto the TLVList.
+func (tl *List) AddUint32(typ byte, v uint32) {
+	tl.AddObject(NewUint32(typ, v))
+}
+
+// AddUint64 pushes an object holding v encoded as 8 big-endian bytes onto the TLVList.
+func (tl *List) AddUint64(typ byte, v uint64) {
+	tl.AddObject(NewUint64(typ, v))
+}
+
+// AddString pushes an object holding the bytes of s onto the TLVList.
+func (tl *List) AddString(typ byte, s string) {
+	tl.AddObject(NewString(typ, s))
+}
+
+// asUint decodes a TLV object's value as an unsigned integer of size bytes in the given order.
+func asUint(tlv TLV, size int, order binary.ByteOrder) (uint64, error) {
+	if len(tlv.Value()) != size {
+		return 0, ErrInvalidLength
+	}
+	return getUint(tlv.Value(), order), nil
+}
+
+// AsUint16BE decodes a TLV object's value as a big-endian uint16, like Uint16.
+func AsUint16BE(tlv TLV) (uint16, error) {
+	v, err := asUint(tlv, 2, binary.BigEndian)
+	return uint16(v), err
+}
+
+// AsUint16LE decodes a TLV object's value as a little-endian uint16.
+// It returns ErrInvalidLength if the value is not exactly 2 bytes.
+func AsUint16LE(tlv TLV) (uint16, error) {
+	v, err := asUint(tlv, 2, binary.LittleEndian)
+	return uint16(v), err
+}
+
+// AsUint32BE decodes a TLV object's value as a big-endian uint32, like Uint32.
+func AsUint32BE(tlv TLV) (uint32, error) {
+	v, err := asUint(tlv, 4, binary.BigEndian)
+	return uint32(v), err
+}
+
+// AsUint32LE decodes a TLV object's value as a little-endian uint32.
+// It returns ErrInvalidLength if the value is not exactly 4 bytes.
+func AsUint32LE(tlv TLV) (uint32, error) {
+	v, err := asUint(tlv, 4, binary.LittleEndian)
+	return uint32(v), err
+}
+
+// AsUint64BE decodes a TLV object's value as a big-endian uint64, like Uint64.
+func AsUint64BE(tlv TLV) (uint64, error) {
+	return asUint(tlv, 8, binary.BigEndian)
+}
+
+// AsUint64LE decodes a TLV object's value as a little-endian uint64.
+// It returns ErrInvalidLength if the value is not exactly 8 bytes.
+func AsUint64LE(tlv TLV) (uint64, error) {
+	return asUint(tlv, 8, binary.LittleEndian)
+}
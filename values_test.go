@@ -0,0 +1,119 @@
+package tlv
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestNewUint(t *testing.T) {
+	tlv16 := NewUint16(TypeTest1, 0x0102)
+	if string(tlv16.Value()) != "\x01\x02" {
+		FailWithError(t, "TestNewUint", fmt.Errorf("uint16 not big-endian"))
+	}
+	if v, err := Uint16(tlv16); err != nil {
+		FailWithError(t, "TestNewUint", err)
+	} else if v != 0x0102 {
+		FailWithError(t, "TestNewUint", fmt.Errorf("uint16 %#x, expected 0x0102", v))
+	}
+
+	tlv32 := NewUint32(TypeTest2, 0x01020304)
+	if string(tlv32.Value()) != "\x01\x02\x03\x04" {
+		FailWithError(t, "TestNewUint", fmt.Errorf("uint32 not big-endian"))
+	}
+	if v, err := Uint32(tlv32); err != nil {
+		FailWithError(t, "TestNewUint", err)
+	} else if v != 0x01020304 {
+		FailWithError(t, "TestNewUint", fmt.Errorf("uint32 %#x, expected 0x01020304", v))
+	}
+
+	tlv64 := NewUint64(TypeTest3, 0x0102030405060708)
+	if string(tlv64.Value()) != "\x01\x02\x03\x04\x05\x06\x07\x08" {
+		FailWithError(t, "TestNewUint", fmt.Errorf("uint64 not big-endian"))
+	}
+	if v, err := Uint64(tlv64); err != nil {
+		FailWithError(t, "TestNewUint", err)
+	} else if v != 0x0102030405060708 {
+		FailWithError(t, "TestNewUint", fmt.Errorf("uint64 %#x, expected 0x0102030405060708", v))
+	}
+}
+
+func TestNewString(t *testing.T) {
+	tlv := NewString(TypeTest1, "foo bar")
+	if !Equal(tlv, New(TypeTest1, []byte("foo bar"))) {
+		FailWithError(t, "TestNewString", errNoMatch)
+	}
+}
+
+func TestUintInvalidLength(t *testing.T) {
+	tlv := New(TypeTest1, []byte{1, 2, 3})
+	if _, err := Uint16(tlv); err != ErrInvalidLength {
+		FailWithError(t, "TestUintInvalidLength",
+			fmt.Errorf("expected ErrInvalidLength, got %v", err))
+	}
+	if _, err := Uint32(tlv); err != ErrInvalidLength {
+		FailWithError(t, "TestUintInvalidLength",
+			fmt.Errorf("expected ErrInvalidLength, got %v", err))
+	}
+	if _, err := Uint64(tlv); err != ErrInvalidLength {
+		FailWithError(t, "TestUintInvalidLength",
+			fmt.Errorf("expected ErrInvalidLength, got %v", err))
+	}
+}
+
+func TestTLVListAddValues(t *testing.T) {
+	tlvl := NewList()
+	tlvl.AddUint16(TypeTest1, 0x0102)
+	tlvl.AddUint32(TypeTest2, 0x01020304)
+	tlvl.AddUint64(TypeTest3, 0x0102030405060708)
+	tlvl.AddString(TypeTest4, "foo bar")
+
+	expected := map[byte]string{
+		TypeTest1: "\x01\x02",
+		TypeTest2: "\x01\x02\x03\x04",
+		TypeTest3: "\x01\x02\x03\x04\x05\x06\x07\x08",
+		TypeTest4: "foo bar",
+	}
+	for typ, val := range expected {
+		tlv, err := tlvl.Get(typ)
+		if err != nil {
+			FailWithError(t, "TestTLVListAddValues", err)
+		} else if string(tlv.Value()) != val {
+			FailWithError(t, "TestTLVListAddValues",
+				fmt.Errorf("type %d: value %q, expected %q", typ, tlv.Value(), val))
+		}
+	}
+}
+
+func TestAsUint(t *testing.T) {
+	val := []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08}
+
+	if v, err := AsUint16BE(New(TypeTest1, val[:2])); err != nil || v != 0x0102 {
+		FailWithError(t, "TestAsUint", fmt.Errorf("AsUint16BE = %#x, %v", v, err))
+	}
+	if v, err := AsUint16LE(New(TypeTest1, val[:2])); err != nil || v != 0x0201 {
+		FailWithError(t, "TestAsUint", fmt.Errorf("AsUint16LE = %#x, %v", v, err))
+	}
+	if v, err := AsUint32BE(New(TypeTest1, val[:4])); err != nil || v != 0x01020304 {
+		FailWithError(t, "TestAsUint", fmt.Errorf("AsUint32BE = %#x, %v", v, err))
+	}
+	if v, err := AsUint32LE(New(TypeTest1, val[:4])); err != nil || v != 0x04030201 {
+		FailWithError(t, "TestAsUint", fmt.Errorf("AsUint32LE = %#x, %v", v, err))
+	}
+	if v, err := AsUint64BE(New(TypeTest1, val)); err != nil || v != 0x0102030405060708 {
+		FailWithError(t, "TestAsUint", fmt.Errorf("AsUint64BE = %#x, %v", v, err))
+	}
+	if v, err := AsUint64LE(New(TypeTest1, val)); err != nil || v != 0x0807060504030201 {
+		FailWithError(t, "TestAsUint", fmt.Errorf("AsUint64LE = %#x, %v", v, err))
+	}
+
+	short := New(TypeTest1, val[:3])
+	if _, err := AsUint16LE(short); err != ErrInvalidLength {
+		FailWithError(t, "TestAsUint", fmt.Errorf("expected ErrInvalidLength, got %v", err))
+	}
+	if _, err := AsUint32BE(short); err != ErrInvalidLength {
+		FailWithError(t, "TestAsUint", fmt.Errorf("expected ErrInvalidLength, got %v", err))
+	}
+	if _, err := AsUint64LE(short); err != ErrInvalidLength {
+		FailWithError(t, "TestAsUint", fmt.Errorf("expected ErrInvalidLength, got %v", err))
+	}
+}